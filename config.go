@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FutureSharks/flipdot-clock/flipdot"
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+// fileConfig is the JSON configuration file given with -config.
+// Everything except the panel positions and clock schedule can also be set with flags,
+// flags given on the command line override the file.
+type fileConfig struct {
+	Serial    serialConfig `json:"serial"`
+	Outputs   []string     `json:"outputs"`
+	FontFiles []string     `json:"font_files"`
+	// FontSpacing is the spacing of fonts by name
+	FontSpacing map[string]spacingConfig `json:"font_spacing"`
+	Layout      layoutConfig             `json:"layout"`
+	Mode        string                   `json:"mode"`
+	Text        textConfig               `json:"text"`
+	Clock       clockConfig              `json:"clock"`
+	HTTPListen  string                   `json:"http_listen"`
+	Debug       bool                     `json:"debug"`
+}
+
+type serialConfig struct {
+	Port                string `json:"port"`
+	Baud                int    `json:"baud"`
+	Buffered            bool   `json:"buffered"`
+	FullRefreshInterval string `json:"full_refresh_interval"`
+}
+
+// layoutConfig is either a layout string the same as -layout, or the position of every panel
+type layoutConfig struct {
+	Spec       string        `json:"spec"`
+	ModuleType string        `json:"module_type"`
+	Rotation   int           `json:"rotation"`
+	Panels     []panelConfig `json:"panels"`
+}
+
+type panelConfig struct {
+	Address    int    `json:"address"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	ModuleType string `json:"module_type"`
+	Rotation   *int   `json:"rotation"`
+}
+
+type textConfig struct {
+	Text string `json:"text"`
+	Loop bool   `json:"loop"`
+	Font string `json:"font"`
+	// ScrollSpeed is deprecated, it sets ColumnsPerSecond from a speed between 1 and 9 if ColumnsPerSecond is not set
+	ScrollSpeed      int      `json:"scroll_speed"`
+	ColumnsPerSecond float64  `json:"columns_per_second"`
+	Static           bool     `json:"static"`
+	Align            string   `json:"align"`
+	VAlign           string   `json:"valign"`
+	Wrap             bool     `json:"wrap"`
+	Effect           string   `json:"effect"`
+	PauseStart       string   `json:"pause_start"`
+	PauseEnd         string   `json:"pause_end"`
+	StripAccents     *bool    `json:"strip_accents"`
+	FallbackFonts    []string `json:"fallback_fonts"`
+	Missing          string   `json:"missing"`
+}
+
+type spacingConfig struct {
+	Letter  *int           `json:"letter"`
+	Kerning map[string]int `json:"kerning"`
+}
+
+type clockConfig struct {
+	Face       string           `json:"face"`
+	Format     string           `json:"format"`
+	Font       string           `json:"font"`
+	Date       string           `json:"date"`
+	PMDot      bool             `json:"pm_dot"`
+	SecondsBar bool             `json:"seconds_bar"`
+	Zones      []zoneConfig     `json:"zones"`
+	ZoneDwell  string           `json:"zone_dwell"`
+	Schedule   []scheduleConfig `json:"schedule"`
+}
+
+type zoneConfig struct {
+	Label string `json:"label"`
+	Zone  string `json:"zone"`
+	Dwell string `json:"dwell"`
+}
+
+type scheduleConfig struct {
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// options are the settings from the flags and the config file
+type options struct {
+	serialPort          string
+	baudRate            int
+	serialBuffered      bool
+	fullRefreshInterval time.Duration
+	terminal            bool
+	outputs             stringList
+	layout              string
+	moduleType          string
+	panelRotation       int
+	testPattern         bool
+	clock               bool
+	clockFace           string
+	clockFormat         string
+	clockFont           string
+	clockDate           string
+	clockPMDot          bool
+	clockSecondsBar     bool
+	clockZones          clockZoneList
+	clockZoneDwell      time.Duration
+	text                string
+	textLoop            bool
+	textStatic          bool
+	textAlign           string
+	textVAlign          string
+	textWrap            bool
+	textEffect          string
+	pauseStart          time.Duration
+	pauseEnd            time.Duration
+	stripAccents        bool
+	fallbackFonts       stringList
+	missingChars        string
+	textSize            string
+	fontFiles           stringList
+	columnsPerSecond    float64
+	httpListen          string
+	debug               bool
+
+	// only set by the config file
+	panels        []flipdot.Panel
+	clockSchedule flipdot.Schedule
+}
+
+// loadConfig reads a config file, unknown keys are an error so typos are not silently ignored
+func loadConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	config := &fileConfig{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("invalid config file %s: key '%s' must be a %s, not a %s", path, typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// apply copies the settings in the config file to o, except those in setFlags which were given on the command line.
+// Errors name the key with the invalid value.
+func (c *fileConfig) apply(o *options, setFlags map[string]bool) error {
+	// fromFile returns true if the file has a value for a setting that was not given on the command line
+	fromFile := func(flagName string, inFile bool) bool {
+		return inFile && !setFlags[flagName]
+	}
+
+	if fromFile("serial-port", c.Serial.Port != "") {
+		o.serialPort = c.Serial.Port
+	}
+	if c.Serial.Baud < 0 {
+		return fmt.Errorf("serial.baud: must be positive, got %d", c.Serial.Baud)
+	}
+	if fromFile("serial-baud", c.Serial.Baud != 0) {
+		o.baudRate = c.Serial.Baud
+	}
+	if fromFile("serial-buffered", c.Serial.Buffered) {
+		o.serialBuffered = true
+	}
+	if c.Serial.FullRefreshInterval != "" {
+		interval, err := time.ParseDuration(c.Serial.FullRefreshInterval)
+		if err != nil || interval < 0 {
+			return fmt.Errorf("serial.full_refresh_interval: invalid duration '%s', e.g. '10m'", c.Serial.FullRefreshInterval)
+		}
+		if fromFile("full-refresh-interval", true) {
+			o.fullRefreshInterval = interval
+		}
+	}
+
+	if fromFile("output", len(c.Outputs) > 0) {
+		o.outputs = c.Outputs
+	}
+
+	if err := c.Layout.apply(o, setFlags); err != nil {
+		return err
+	}
+
+	if c.Text.ScrollSpeed != 0 && (c.Text.ScrollSpeed < 1 || c.Text.ScrollSpeed > 9) {
+		return fmt.Errorf("text.scroll_speed: must be between 1 and 9, got %d", c.Text.ScrollSpeed)
+	}
+	if c.Text.ColumnsPerSecond < 0 {
+		return fmt.Errorf("text.columns_per_second: must be positive, got %g", c.Text.ColumnsPerSecond)
+	}
+	// both flags set the speed, so either on the command line overrides the file
+	if !setFlags["text-scroll-speed"] {
+		if fromFile("text-columns-per-second", c.Text.ColumnsPerSecond != 0) {
+			o.columnsPerSecond = c.Text.ColumnsPerSecond
+		} else if fromFile("text-columns-per-second", c.Text.ScrollSpeed != 0) {
+			o.columnsPerSecond = flipdot.ScrollSpeed(c.Text.ScrollSpeed)
+		}
+	}
+	// fonts given with -font-file have already been loaded
+	if fromFile("font-file", len(c.FontFiles) > 0) {
+		for i, path := range c.FontFiles {
+			if _, err := fonts.LoadFont(path); err != nil {
+				return fmt.Errorf("font_files[%d]: %v", i, err)
+			}
+		}
+		o.fontFiles = c.FontFiles
+	}
+	for name, spacing := range c.FontSpacing {
+		s := fonts.Spacing{Letter: fonts.DefaultSpacing.Letter, Kerning: spacing.Kerning}
+		if spacing.Letter != nil {
+			s.Letter = *spacing.Letter
+		}
+		if err := fonts.SetSpacing(name, s); err != nil {
+			return fmt.Errorf("font_spacing.%s: %v", name, err)
+		}
+	}
+	if c.Text.Font != "" {
+		if _, err := fonts.Lookup(c.Text.Font); err != nil {
+			return fmt.Errorf("text.font: %v", err)
+		}
+	}
+	if fromFile("text-size", c.Text.Font != "") {
+		o.textSize = c.Text.Font
+	}
+	if fromFile("text-loop", c.Text.Loop) {
+		o.textLoop = true
+	}
+	if fromFile("text-static", c.Text.Static) {
+		o.textStatic = true
+	}
+	if c.Text.Align != "" {
+		if _, err := flipdot.ParseAlign(c.Text.Align); err != nil {
+			return fmt.Errorf("text.align: %v", err)
+		}
+	}
+	if fromFile("text-align", c.Text.Align != "") {
+		o.textAlign = c.Text.Align
+	}
+	if c.Text.VAlign != "" {
+		if _, err := flipdot.ParseVAlign(c.Text.VAlign); err != nil {
+			return fmt.Errorf("text.valign: %v", err)
+		}
+	}
+	if fromFile("text-valign", c.Text.VAlign != "") {
+		o.textVAlign = c.Text.VAlign
+	}
+	if fromFile("text-wrap", c.Text.Wrap) {
+		o.textWrap = true
+	}
+	if c.Text.Effect != "" {
+		if _, err := flipdot.ParseTextEffect(c.Text.Effect); err != nil {
+			return fmt.Errorf("text.effect: %v", err)
+		}
+	}
+	if fromFile("text-effect", c.Text.Effect != "") {
+		o.textEffect = c.Text.Effect
+	}
+	if c.Text.PauseStart != "" {
+		pause, err := time.ParseDuration(c.Text.PauseStart)
+		if err != nil || pause < 0 {
+			return fmt.Errorf("text.pause_start: invalid duration '%s', e.g. '2s'", c.Text.PauseStart)
+		}
+		if fromFile("text-pause-start", true) {
+			o.pauseStart = pause
+		}
+	}
+	if c.Text.PauseEnd != "" {
+		pause, err := time.ParseDuration(c.Text.PauseEnd)
+		if err != nil || pause < 0 {
+			return fmt.Errorf("text.pause_end: invalid duration '%s', e.g. '2s'", c.Text.PauseEnd)
+		}
+		if fromFile("text-pause-end", true) {
+			o.pauseEnd = pause
+		}
+	}
+	if fromFile("text-strip-accents", c.Text.StripAccents != nil) {
+		o.stripAccents = *c.Text.StripAccents
+	}
+	for i, name := range c.Text.FallbackFonts {
+		if _, err := fonts.Lookup(name); err != nil {
+			return fmt.Errorf("text.fallback_fonts[%d]: %v", i, err)
+		}
+	}
+	if fromFile("text-fallback-font", len(c.Text.FallbackFonts) > 0) {
+		o.fallbackFonts = c.Text.FallbackFonts
+	}
+	if c.Text.Missing != "" {
+		if _, err := fonts.ParseMissingStrategy(c.Text.Missing); err != nil {
+			return fmt.Errorf("text.missing: %v", err)
+		}
+	}
+	if fromFile("text-missing", c.Text.Missing != "") {
+		o.missingChars = c.Text.Missing
+	}
+
+	// a mode given on the command line replaces the mode in the file
+	if !setFlags["clock"] && !setFlags["text"] && !setFlags["test-pattern"] {
+		switch c.Mode {
+		case "":
+		case "clock":
+			o.clock = true
+		case "text":
+			if c.Text.Text == "" {
+				return fmt.Errorf("text.text: must be set when mode is 'text'")
+			}
+			o.text = c.Text.Text
+		case "test-pattern":
+			o.testPattern = true
+		default:
+			return fmt.Errorf("mode: must be one of 'clock', 'text' or 'test-pattern', got '%s'", c.Mode)
+		}
+	}
+
+	if c.Clock.Face != "" {
+		if _, err := flipdot.LookupClockFace(c.Clock.Face); err != nil {
+			return fmt.Errorf("clock.face: %v", err)
+		}
+	}
+	if fromFile("clock-face", c.Clock.Face != "") {
+		o.clockFace = c.Clock.Face
+	}
+	if _, err := flipdot.ClockLayout(c.Clock.Format); err != nil {
+		return fmt.Errorf("clock.format: %v", err)
+	}
+	if fromFile("clock-format", c.Clock.Format != "") {
+		o.clockFormat = c.Clock.Format
+	}
+	if c.Clock.Font != "" {
+		if _, err := fonts.Lookup(c.Clock.Font); err != nil {
+			return fmt.Errorf("clock.font: %v", err)
+		}
+	}
+	if fromFile("clock-font", c.Clock.Font != "") {
+		o.clockFont = c.Clock.Font
+	}
+	if _, err := flipdot.ClockLayout(c.Clock.Date); err != nil {
+		return fmt.Errorf("clock.date: %v", err)
+	}
+	if fromFile("clock-date", c.Clock.Date != "") {
+		o.clockDate = c.Clock.Date
+	}
+	if fromFile("clock-pm-dot", c.Clock.PMDot) {
+		o.clockPMDot = true
+	}
+	if fromFile("clock-seconds-bar", c.Clock.SecondsBar) {
+		o.clockSecondsBar = true
+	}
+	var zones clockZoneList
+	for i, zone := range c.Clock.Zones {
+		key := fmt.Sprintf("clock.zones[%d]", i)
+		z, err := flipdot.LoadClockZone(zone.Label, zone.Zone)
+		if err != nil {
+			return fmt.Errorf("%s.zone: %v", key, err)
+		}
+		if zone.Dwell != "" {
+			z.Dwell, err = time.ParseDuration(zone.Dwell)
+			if err != nil || z.Dwell <= 0 {
+				return fmt.Errorf("%s.dwell: invalid duration '%s', e.g. '10s'", key, zone.Dwell)
+			}
+		}
+		zones = append(zones, z)
+	}
+	if fromFile("clock-zone", len(zones) > 0) {
+		o.clockZones = zones
+	}
+	if c.Clock.ZoneDwell != "" {
+		dwell, err := time.ParseDuration(c.Clock.ZoneDwell)
+		if err != nil || dwell <= 0 {
+			return fmt.Errorf("clock.zone_dwell: invalid duration '%s', e.g. '10s'", c.Clock.ZoneDwell)
+		}
+		if fromFile("clock-zone-dwell", true) {
+			o.clockZoneDwell = dwell
+		}
+	}
+	for i, window := range c.Clock.Schedule {
+		key := fmt.Sprintf("clock.schedule[%d]", i)
+		w := flipdot.ScheduleWindow{}
+		for _, name := range window.Days {
+			day, err := flipdot.ParseWeekday(name)
+			if err != nil {
+				return fmt.Errorf("%s.days: %v", key, err)
+			}
+			w.Days = append(w.Days, day)
+		}
+		var err error
+		if w.Start, err = flipdot.ParseTimeOfDay(window.Start); err != nil {
+			return fmt.Errorf("%s.start: %v", key, err)
+		}
+		if w.End, err = flipdot.ParseTimeOfDay(window.End); err != nil {
+			return fmt.Errorf("%s.end: %v", key, err)
+		}
+		o.clockSchedule = append(o.clockSchedule, w)
+	}
+
+	if fromFile("http-listen", c.HTTPListen != "") {
+		o.httpListen = c.HTTPListen
+	}
+	if fromFile("debug", c.Debug) {
+		o.debug = true
+	}
+
+	return nil
+}
+
+// apply copies the layout to o, unless the layout was set with flags
+func (l layoutConfig) apply(o *options, setFlags map[string]bool) error {
+	if setFlags["layout"] || setFlags["module-type"] || setFlags["panel-rotation"] {
+		return nil
+	}
+
+	if l.ModuleType != "" {
+		if _, err := flipdot.ModuleTypeByName(l.ModuleType); err != nil {
+			return fmt.Errorf("layout.module_type: %v", err)
+		}
+		o.moduleType = l.ModuleType
+	}
+	if l.Rotation != 0 && l.Rotation != 90 && l.Rotation != 180 && l.Rotation != 270 {
+		return fmt.Errorf("layout.rotation: must be one of 0, 90, 180 or 270, got %d", l.Rotation)
+	}
+	if l.Rotation != 0 {
+		o.panelRotation = l.Rotation
+	}
+
+	if l.Spec != "" && len(l.Panels) > 0 {
+		return fmt.Errorf("layout: only one of 'spec' or 'panels' can be set")
+	}
+	if l.Spec != "" {
+		module, err := flipdot.ModuleTypeByName(o.moduleType)
+		if err != nil {
+			return fmt.Errorf("layout.module_type: %v", err)
+		}
+		if _, err := flipdot.ParseLayout(l.Spec, module, o.panelRotation); err != nil {
+			return fmt.Errorf("layout.spec: %v", err)
+		}
+		o.layout = l.Spec
+		return nil
+	}
+
+	for i, p := range l.Panels {
+		key := fmt.Sprintf("layout.panels[%d]", i)
+		if p.Address < 0 || p.Address > 0xff {
+			return fmt.Errorf("%s.address: must be between 0 and 255, got %d", key, p.Address)
+		}
+		moduleName := o.moduleType
+		if p.ModuleType != "" {
+			moduleName = p.ModuleType
+		}
+		module, err := flipdot.ModuleTypeByName(moduleName)
+		if err != nil {
+			return fmt.Errorf("%s.module_type: %v", key, err)
+		}
+		rotation := o.panelRotation
+		if p.Rotation != nil {
+			rotation = *p.Rotation
+		}
+		o.panels = append(o.panels, flipdot.Panel{
+			Address:  byte(p.Address),
+			X:        p.X,
+			Y:        p.Y,
+			Width:    module.Width,
+			Height:   module.Height,
+			Rotation: rotation,
+		})
+	}
+	if len(o.panels) > 0 {
+		if err := (flipdot.Layout{Panels: o.panels}).Validate(); err != nil {
+			return fmt.Errorf("layout.panels: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FutureSharks/flipdot-clock/flipdot"
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+// defaultOptions are the flag defaults
+func defaultOptions() *options {
+	return &options{
+		serialPort:       "/dev/ttyS0",
+		baudRate:         57600,
+		layout:           "1;2",
+		moduleType:       "7x28",
+		clockFace:        flipdot.DefaultClockFace,
+		clockFormat:      flipdot.DefaultClockFormat,
+		clockZoneDwell:   flipdot.DefaultClockZoneDwell,
+		textSize:         "large",
+		columnsPerSecond: flipdot.DefaultColumnsPerSecond,
+		stripAccents:     true,
+		missingChars:     "replace",
+		textAlign:        "center",
+		textVAlign:       "middle",
+		textEffect:       "scroll-left",
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"serial": {"port": "/dev/ttyUSB0", "baud": 9600, "buffered": true, "full_refresh_interval": "10m"},
+		"outputs": ["serial", "web://:8081?on-error=disable"],
+		"layout": {
+			"module_type": "7x14",
+			"panels": [
+				{"address": 1, "x": 0, "y": 0},
+				{"address": 2, "x": 14, "y": 0, "rotation": 180},
+				{"address": 3, "x": 0, "y": 7, "module_type": "7x28"}
+			]
+		},
+		"mode": "clock",
+		"text": {"font": "small", "scroll_speed": 7, "columns_per_second": 20, "loop": true, "static": true, "align": "left", "valign": "top", "wrap": true, "effect": "bounce", "pause_start": "2s", "pause_end": "1500ms", "strip_accents": false, "fallback_fonts": ["large"], "missing": "skip"},
+		"clock": {
+			"face": "analog",
+			"format": "%-I:%M",
+			"font": "condensed",
+			"date": "%a %-d",
+			"pm_dot": true,
+			"seconds_bar": true,
+			"zones": [{"label": "UTC", "zone": "UTC"}, {"label": "TYO", "zone": "Asia/Tokyo", "dwell": "20s"}],
+			"zone_dwell": "5s",
+			"schedule": [{"days": ["mon", "tue"], "start": "07:00", "end": "23:30"}]
+		},
+		"http_listen": ":8080"
+	}`)
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := defaultOptions()
+	if err := config.apply(o, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := defaultOptions()
+	expected.serialPort = "/dev/ttyUSB0"
+	expected.baudRate = 9600
+	expected.serialBuffered = true
+	expected.fullRefreshInterval = 10 * time.Minute
+	expected.outputs = stringList{"serial", "web://:8081?on-error=disable"}
+	expected.moduleType = "7x14"
+	expected.panels = []flipdot.Panel{
+		{Address: 1, X: 0, Y: 0, Width: 14, Height: 7},
+		{Address: 2, X: 14, Y: 0, Width: 14, Height: 7, Rotation: 180},
+		{Address: 3, X: 0, Y: 7, Width: 28, Height: 7},
+	}
+	expected.clock = true
+	expected.textSize = "small"
+	expected.columnsPerSecond = 20
+	expected.textLoop = true
+	expected.textStatic = true
+	expected.textAlign = "left"
+	expected.textVAlign = "top"
+	expected.textWrap = true
+	expected.textEffect = "bounce"
+	expected.pauseStart = 2 * time.Second
+	expected.pauseEnd = 1500 * time.Millisecond
+	expected.stripAccents = false
+	expected.fallbackFonts = stringList{"large"}
+	expected.missingChars = "skip"
+	expected.clockFace = "analog"
+	expected.clockFormat = "%-I:%M"
+	expected.clockFont = "condensed"
+	expected.clockDate = "%a %-d"
+	expected.clockPMDot = true
+	expected.clockSecondsBar = true
+	expected.clockZones = clockZoneList{
+		{Label: "UTC", Location: time.UTC},
+		{Label: "TYO", Location: mustLoadLocation(t, "Asia/Tokyo"), Dwell: 20 * time.Second},
+	}
+	expected.clockZoneDwell = 5 * time.Second
+	expected.clockSchedule = flipdot.Schedule{
+		{Days: []time.Weekday{time.Monday, time.Tuesday}, Start: 7 * time.Hour, End: 23*time.Hour + 30*time.Minute},
+	}
+	expected.httpListen = ":8080"
+
+	if !reflect.DeepEqual(o, expected) {
+		t.Errorf("unexpected options:\nexpected: %+v\nactual:   %+v", expected, o)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone not available: %v", err)
+	}
+	return location
+}
+
+func TestConfigFontSpacing(t *testing.T) {
+	config, err := loadConfig(writeConfig(t, `{"font_spacing": {"condensed": {"letter": 2, "kerning": {"1:": -1}}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { fonts.SetSpacing("condensed", fonts.DefaultSpacing) })
+
+	if err := config.apply(defaultOptions(), map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spacing := fonts.SpacingOf("condensed")
+	if spacing.Between('1', ':') != 1 || spacing.Between('2', ':') != 2 {
+		t.Errorf("unexpected spacing %+v", spacing)
+	}
+}
+
+func TestConfigFlagsOverrideFile(t *testing.T) {
+	config, err := loadConfig(writeConfig(t, `{
+		"serial": {"port": "/dev/ttyUSB0", "baud": 9600},
+		"layout": {"spec": "1,2", "module_type": "7x14"},
+		"mode": "text",
+		"text": {"text": "from the file", "font": "small"}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the values given on the command line
+	o := defaultOptions()
+	o.serialPort = "/dev/ttyS1"
+	o.layout = "3"
+	o.clock = true
+	setFlags := map[string]bool{"serial-port": true, "layout": true, "clock": true}
+
+	if err := config.apply(o, setFlags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if o.serialPort != "/dev/ttyS1" {
+		t.Errorf("expected serial port from the flag, got %s", o.serialPort)
+	}
+	if o.baudRate != 9600 {
+		t.Errorf("expected baud rate from the file, got %d", o.baudRate)
+	}
+	if o.layout != "3" || o.moduleType != "7x28" {
+		t.Errorf("expected layout from the flags, got %s with %s modules", o.layout, o.moduleType)
+	}
+	if !o.clock || o.text != "" {
+		t.Errorf("expected clock mode from the flag, got clock %v and text '%s'", o.clock, o.text)
+	}
+	if o.textSize != "small" {
+		t.Errorf("expected font from the file, got %s", o.textSize)
+	}
+}
+
+// Test that the deprecated scroll_speed sets the columns per second unless a speed is given in columns per second
+// or on the command line
+func TestConfigScrollSpeed(t *testing.T) {
+	config, err := loadConfig(writeConfig(t, `{"text": {"scroll_speed": 9}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o := defaultOptions()
+	if err := config.apply(o, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.columnsPerSecond != 100 {
+		t.Errorf("expected 100 columns per second for scroll speed 9, got %g", o.columnsPerSecond)
+	}
+
+	for _, flagName := range []string{"text-columns-per-second", "text-scroll-speed"} {
+		o := defaultOptions()
+		o.columnsPerSecond = 20
+		if err := config.apply(o, map[string]bool{flagName: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if o.columnsPerSecond != 20 {
+			t.Errorf("expected the speed of -%s, got %g", flagName, o.columnsPerSecond)
+		}
+	}
+}
+
+func TestConfigErrorsNameKey(t *testing.T) {
+	testCases := []struct {
+		config string
+		key    string
+	}{
+		{config: `{"serail": {}}`, key: `"serail"`},
+		{config: `{"serial": {"baud": "fast"}}`, key: "serial.baud"},
+		{config: `{"serial": {"baud": -1}}`, key: "serial.baud"},
+		{config: `{"serial": {"full_refresh_interval": "often"}}`, key: "serial.full_refresh_interval"},
+		{config: `{"layout": {"module_type": "7x99"}}`, key: "layout.module_type"},
+		{config: `{"layout": {"rotation": 45}}`, key: "layout.rotation"},
+		{config: `{"layout": {"spec": "1;x"}}`, key: "layout.spec"},
+		{config: `{"layout": {"panels": [{"address": 1}, {"address": 999, "y": 7}]}}`, key: "layout.panels[1].address"},
+		{config: `{"layout": {"panels": [{"address": 1}, {"address": 2}]}}`, key: "layout.panels"},
+		{config: `{"mode": "party"}`, key: "mode"},
+		{config: `{"mode": "text"}`, key: "text.text"},
+		{config: `{"text": {"font": "huge"}}`, key: "text.font"},
+		{config: `{"font_files": ["missing.bdf"]}`, key: "font_files[0]"},
+		{config: `{"text": {"fallback_fonts": ["large", "huge"]}}`, key: "text.fallback_fonts[1]"},
+		{config: `{"text": {"missing": "ignore"}}`, key: "text.missing"},
+		{config: `{"text": {"align": "justify"}}`, key: "text.align"},
+		{config: `{"clock": {"font": "huge"}}`, key: "clock.font"},
+		{config: `{"clock": {"face": "cuckoo"}}`, key: "clock.face"},
+		{config: `{"clock": {"format": "%H:%Q"}}`, key: "clock.format"},
+		{config: `{"clock": {"date": "%d %"}}`, key: "clock.date"},
+		{config: `{"clock": {"zones": [{"label": "BER", "zone": "Europe/Berln"}]}}`, key: "clock.zones[0].zone"},
+		{config: `{"clock": {"zones": [{"label": "BER"}]}}`, key: "clock.zones[0].zone"},
+		{config: `{"clock": {"zones": [{"zone": "UTC", "dwell": "10"}]}}`, key: "clock.zones[0].dwell"},
+		{config: `{"clock": {"zone_dwell": "-1s"}}`, key: "clock.zone_dwell"},
+		{config: `{"text": {"effect": "spin"}}`, key: "text.effect"},
+		{config: `{"text": {"columns_per_second": -5}}`, key: "text.columns_per_second"},
+		{config: `{"text": {"pause_start": "2"}}`, key: "text.pause_start"},
+		{config: `{"text": {"pause_end": "-1s"}}`, key: "text.pause_end"},
+		{config: `{"font_spacing": {"huge": {"letter": 1}}}`, key: "font_spacing.huge"},
+		{config: `{"font_spacing": {"condensed": {"kerning": {"1": -1}}}}`, key: "font_spacing.condensed"},
+		{config: `{"text": {"valign": "center"}}`, key: "text.valign"},
+		{config: `{"text": {"scroll_speed": 10}}`, key: "text.scroll_speed"},
+		{config: `{"clock": {"schedule": [{"start": "07:00", "end": "25:00"}]}}`, key: "clock.schedule[0].end"},
+		{config: `{"clock": {"schedule": [{"days": ["someday"], "start": "07:00", "end": "08:00"}]}}`, key: "clock.schedule[0].days"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			config, err := loadConfig(writeConfig(t, tc.config))
+			if err == nil {
+				err = config.apply(defaultOptions(), map[string]bool{})
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tc.key) {
+				t.Errorf("expected error to name %s, got: %v", tc.key, err)
+			}
+		})
+	}
+}
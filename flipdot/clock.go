@@ -0,0 +1,87 @@
+package flipdot
+
+import (
+	"context"
+	"time"
+)
+
+// maxClockSleep is the longest the clock sleeps before it checks the time again, as the sleep does not notice
+// when the system time is changed
+const maxClockSleep = 5 * time.Second
+
+// Clock is where the clock gets the time from and how it waits for the next update,
+// so that RunClock can be tested without waiting for real minutes
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for the duration or until ctx is cancelled, in which case it returns the context's error
+	Sleep(ctx context.Context, duration time.Duration) error
+}
+
+// systemClock is the Clock of the system time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, duration time.Duration) error {
+	return sleep(ctx, duration)
+}
+
+// timeSource returns the clock of the display, the system clock if it has none
+func (d *Display) timeSource() Clock {
+	if d.clock == nil {
+		return systemClock{}
+	}
+	return d.clock
+}
+
+// nextClockUpdate returns when the clock next changes after now, the start of the next interval.
+// Intervals are counted from the zero time, so minutes start at 0 seconds in every time zone with an offset of
+// whole minutes, also when daylight saving time starts or ends.
+func nextClockUpdate(now time.Time, interval time.Duration) time.Time {
+	return now.Truncate(interval).Add(interval)
+}
+
+// nextClockTime returns when the clock next changes after now, because the time shown by the face changes,
+// e.g. at the start of the next minute, or the next clock zone is shown
+func (d *Display) nextClockTime(now time.Time) time.Time {
+	zone, nextZone := d.clockZoneAt(now)
+	next := d.face().Next(d, now.In(zone.Location))
+	if !nextZone.IsZero() && nextZone.Before(next) {
+		next = nextZone
+	}
+	return next
+}
+
+// RunClock shows the time with the clock face and updates it when it changes, e.g. at the start of every minute,
+// and when the next clock zone is shown, until ctx is cancelled. Outside the clock schedule the display is blank.
+// The clock sleeps at most maxClockSleep at a time and works out the next update again from the clock's time
+// after every sleep, so the clock does not drift and follows soon when the system time is changed.
+func (d *Display) RunClock(ctx context.Context) error {
+	clock := d.timeSource()
+	for {
+		now := clock.Now()
+		next := d.nextClockTime(now)
+		var err error
+		if d.clockSchedule.Active(now) {
+			err = d.showTime(ctx, now, next)
+		} else {
+			err = d.Clear()
+		}
+		if err != nil {
+			return err
+		}
+
+		for {
+			now = clock.Now()
+			// a changed system time can make the next update earlier or later than the one shown was for
+			if !now.Before(next) || !d.nextClockTime(now).Equal(next) {
+				break
+			}
+			if err := clock.Sleep(ctx, min(next.Sub(now), maxClockSleep)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package flipdot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when it sleeps.
+// After the sleeps it cancels the clock so RunClock returns.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+	// jumps are added to the time after the sleep with the same index, like the system time being changed
+	jumps  map[int]time.Duration
+	max    int
+	cancel context.CancelFunc
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, duration time.Duration) error {
+	c.now = c.now.Add(duration + c.jumps[len(c.sleeps)])
+	c.sleeps = append(c.sleeps, duration)
+	if len(c.sleeps) == c.max {
+		c.cancel()
+	}
+	return ctx.Err()
+}
+
+func runFakeClock(t *testing.T, display *Display, clock *fakeClock) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock.cancel = cancel
+	display.clock = clock
+	if err := display.RunClock(ctx); err != context.Canceled {
+		t.Fatalf("expected the clock to be cancelled, got %v", err)
+	}
+}
+
+// shownFace is a clock face that records the times it shows
+type shownFace struct {
+	ClockFace
+	shown *[]time.Time
+}
+
+func (f shownFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	*f.shown = append(*f.shown, t)
+	return f.ClockFace.Show(ctx, d, t, label)
+}
+
+func TestRunClock(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 10, 0, 42, 500*int(time.Millisecond), time.UTC)
+	at := func(minute, second int) time.Time {
+		return time.Date(2024, time.March, 4, 10, minute, second, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		display  *Display
+		jumps    map[int]time.Duration
+		max      int
+		expected []time.Time
+	}{
+		{
+			name:     "minutes",
+			display:  &Display{},
+			max:      17,
+			expected: []time.Time{start, at(1, 0), at(2, 0)},
+		},
+		{
+			name:     "seconds",
+			display:  &Display{clockFormat: "15:04:05", clockFont: "condensed"},
+			max:      3,
+			expected: []time.Time{start, at(0, 43), at(0, 44)},
+		},
+		{
+			// while the clock waits for 10:01:00 the system time is changed forward by a minute,
+			// then back by one and a half minutes
+			name:     "time changed",
+			display:  &Display{},
+			jumps:    map[int]time.Duration{1: time.Minute, 3: -90 * time.Second},
+			max:      11,
+			expected: []time.Time{start, at(1, 52).Add(500 * time.Millisecond), at(0, 30), at(1, 0)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var shown []time.Time
+			mock := &MockDisplayOutput{}
+			tc.display.output = mock
+			tc.display.layout = DefaultLayout()
+			tc.display.clockFace = shownFace{ClockFace: digitalFace{}, shown: &shown}
+			clock := &fakeClock{now: start, jumps: tc.jumps, max: tc.max}
+			runFakeClock(t, tc.display, clock)
+
+			if !reflect.DeepEqual(shown, tc.expected) {
+				t.Errorf("expected the clock shown at %v, got %v", tc.expected, shown)
+			}
+			for _, sleep := range clock.sleeps {
+				if sleep <= 0 || sleep > maxClockSleep {
+					t.Errorf("expected sleeps of at most %v, got %v", maxClockSleep, clock.sleeps)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Test that the clock updates on the minute when daylight saving time starts and ends
+func TestNextClockUpdateDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone not available: %v", err)
+	}
+
+	testCases := []struct {
+		now      time.Time
+		expected string
+	}{
+		{now: time.Date(2024, time.March, 31, 1, 59, 30, 0, berlin), expected: "03:00:00 CEST"},
+		{now: time.Date(2024, time.October, 27, 2, 59, 30, 0, time.FixedZone("CEST", 2*60*60)).In(berlin), expected: "02:00:00 CET"},
+		// India is 5 and a half hours ahead of UTC
+		{now: time.Date(2024, time.March, 4, 10, 29, 59, 0, time.FixedZone("IST", 330*60)), expected: "10:30:00 IST"},
+	}
+	for _, tc := range testCases {
+		next := nextClockUpdate(tc.now, time.Minute)
+		if formatted := next.Format("15:04:05 MST"); formatted != tc.expected {
+			t.Errorf("expected the update after %v at %s, got %s", tc.now, tc.expected, formatted)
+		}
+		if wait := next.Sub(tc.now); wait != 30*time.Second && wait != time.Second {
+			t.Errorf("expected to wait 30 seconds or 1 second after %v, got %v", tc.now, wait)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package flipdot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// strftimeDirectives are the Go layouts of the strftime directives ClockLayout supports
+var strftimeDirectives = map[string]string{
+	"H":  "15",
+	"I":  "03",
+	"-I": "3",
+	"l":  "3",
+	"M":  "04",
+	"S":  "05",
+	"p":  "PM",
+	"P":  "pm",
+	"a":  "Mon",
+	"A":  "Monday",
+	"b":  "Jan",
+	"h":  "Jan",
+	"B":  "January",
+	"d":  "02",
+	"-d": "2",
+	"e":  "_2",
+	"m":  "01",
+	"-m": "1",
+	"y":  "06",
+	"Y":  "2006",
+	"j":  "002",
+	"Z":  "MST",
+	"z":  "-0700",
+	"%":  "%",
+}
+
+// ClockLayout returns the Go time layout of a clock format. A format with a '%' is a strftime format,
+// e.g. "%H:%M", anything else is already a Go time layout, e.g. "15:04".
+// Text between the directives of a strftime format must not contain Go layout elements like "Mon".
+func ClockLayout(format string) (string, error) {
+	if !strings.Contains(format, "%") {
+		return format, nil
+	}
+
+	var layout strings.Builder
+	chars := []rune(format)
+	for i := 0; i < len(chars); i++ {
+		if chars[i] != '%' {
+			layout.WriteRune(chars[i])
+			continue
+		}
+		if i+1 >= len(chars) {
+			return "", fmt.Errorf("strftime format '%s' ends with '%%'", format)
+		}
+		directive := string(chars[i+1])
+		if chars[i+1] == '-' && i+2 < len(chars) {
+			directive = string(chars[i+1 : i+3])
+		}
+		goLayout, ok := strftimeDirectives[directive]
+		if !ok {
+			return "", fmt.Errorf("strftime directive '%%%s' in '%s' not supported", directive, format)
+		}
+		layout.WriteString(goLayout)
+		i += len([]rune(directive))
+	}
+	return layout.String(), nil
+}
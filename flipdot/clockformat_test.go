@@ -0,0 +1,41 @@
+package flipdot
+
+import "testing"
+
+func TestClockLayout(t *testing.T) {
+	testCases := []struct {
+		format      string
+		expected    string
+		expectError bool
+	}{
+		{format: "15:04", expected: "15:04"},
+		{format: "%H:%M", expected: "15:04"},
+		{format: "%I:%M:%S %p", expected: "03:04:05 PM"},
+		{format: "%-I:%M", expected: "3:04"},
+		{format: "%a %-d %b", expected: "Mon 2 Jan"},
+		{format: "%d/%m/%y", expected: "02/01/06"},
+		{format: "100%%", expected: "100%"},
+		{format: "", expected: ""},
+		{format: "%H:%Q", expectError: true},
+		{format: "%-H", expectError: true},
+		{format: "%H %", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			layout, err := ClockLayout(tc.format)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got '%s'", layout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if layout != tc.expected {
+				t.Errorf("expected '%s', got '%s'", tc.expected, layout)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package flipdot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	fonts "github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TextEffect is how text is moved across the display
+type TextEffect int
+
+const (
+	// EffectScrollLeft scrolls the text in from the right and out to the left
+	EffectScrollLeft TextEffect = iota
+	// EffectScrollRight scrolls the text in from the left and out to the right
+	EffectScrollRight
+	// EffectScrollUp scrolls the lines of the text in from the bottom one after another
+	EffectScrollUp
+	// EffectScrollDown scrolls the lines of the text in from the top one after another
+	EffectScrollDown
+	// EffectBounce moves the text to the left until its end is shown and back again,
+	// for text slightly wider than the display
+	EffectBounce
+	// EffectTypewriter shows the characters one by one
+	EffectTypewriter
+)
+
+var textEffectNames = map[string]TextEffect{
+	"scroll-left":  EffectScrollLeft,
+	"scroll-right": EffectScrollRight,
+	"scroll-up":    EffectScrollUp,
+	"scroll-down":  EffectScrollDown,
+	"bounce":       EffectBounce,
+	"typewriter":   EffectTypewriter,
+}
+
+// ParseTextEffect returns the text effect with a name of "scroll-left", "scroll-right", "scroll-up",
+// "scroll-down", "bounce" or "typewriter"
+func ParseTextEffect(name string) (TextEffect, error) {
+	effect, ok := textEffectNames[name]
+	if !ok {
+		return 0, fmt.Errorf("text effect '%s' not supported, must be one of 'scroll-left', 'scroll-right', 'scroll-up', 'scroll-down', 'bounce' or 'typewriter'", name)
+	}
+	return effect, nil
+}
+
+// effectFrame is one step of a text effect
+type effectFrame struct {
+	displayData []uint32
+	// key frames are held for TextOptions.PauseStart or PauseEnd, see holdKeyFrames
+	key  bool
+	hold time.Duration
+}
+
+// ShowTextEffect shows text with opts.Effect until it finishes or ctx is cancelled.
+// The text moves opts.Speed columns, or rows, per second. Frames are dropped when the output
+// is too slow to keep up. The frame where the text first fills the display, or is shown whole,
+// is held for opts.PauseStart and the frame where it last does for opts.PauseEnd.
+// Scrolling up or down holds every line, the last one for opts.PauseEnd.
+func (d *Display) ShowTextEffect(ctx context.Context, text string, opts TextOptions) error {
+	font, err := fonts.Lookup(opts.Font)
+	if err != nil {
+		return err
+	}
+
+	var frames []effectFrame
+	var substitutions []fonts.Substitution
+	switch opts.Effect {
+	case EffectScrollUp, EffectScrollDown:
+		block, blockSubstitutions, err := d.layoutText(text, font, opts.Wrap)
+		if err != nil {
+			return err
+		}
+		substitutions = blockSubstitutions
+		frames = d.verticalFrames(block, font, opts)
+	case EffectTypewriter:
+		frames, substitutions, err = d.typewriterFrames(strings.ReplaceAll(text, "\n", " "), font, opts.Align)
+		if err != nil {
+			return err
+		}
+	default:
+		columns, lineSubstitutions, err := d.renderText(strings.ReplaceAll(text, "\n", " "), font)
+		if err != nil {
+			return err
+		}
+		substitutions = lineSubstitutions
+		frames = d.horizontalFrames(columns, font, opts.Effect, opts.Loop)
+	}
+	logSubstitutions(font, substitutions)
+	if len(frames) == 0 {
+		// e.g. blank text with the typewriter, or every character skipped
+		return d.Clear()
+	}
+	holdKeyFrames(frames, opts.PauseStart, opts.PauseEnd)
+
+	ticker := newFrameTicker(opts.Speed)
+	// dropped is the number of frames skipped to catch up with the speed
+	dropped := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i := 0; i < len(frames); {
+			frame := frames[i]
+			err := d.Show(frame.displayData)
+			if err != nil {
+				return err
+			}
+
+			if frame.hold > 0 {
+				if err := sleep(ctx, frame.hold); err != nil {
+					return err
+				}
+				ticker.reset()
+				i++
+				continue
+			}
+			if i == len(frames)-1 && !opts.Loop {
+				// nothing follows the last frame
+				break
+			}
+			steps, err := ticker.wait(ctx)
+			if err != nil {
+				return err
+			}
+			next := nextFrame(frames, i, steps)
+			dropped += next - i - 1
+			i = next
+		}
+
+		if dropped > 0 {
+			log.Warnf("Dropped %d of %d frames, the display can not keep up with %g columns per second", dropped, len(frames), opts.Speed)
+			dropped = 0
+		}
+		if !opts.Loop {
+			return nil
+		}
+	}
+}
+
+// nextFrame returns the frame steps after i. Key frames and the last frame are not skipped.
+func nextFrame(frames []effectFrame, i int, steps int) int {
+	for range steps - 1 {
+		if i+1 >= len(frames)-1 || frames[i+1].key {
+			break
+		}
+		i++
+	}
+	return i + 1
+}
+
+// holdKeyFrames sets how long the key frames are held: the first for pauseStart, the last for pauseEnd
+// and the ones between for pauseStart
+func holdKeyFrames(frames []effectFrame, pauseStart time.Duration, pauseEnd time.Duration) {
+	first, last := -1, -1
+	for i, frame := range frames {
+		if !frame.key {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+		frames[i].hold = pauseStart
+	}
+	if last == -1 {
+		return
+	}
+	frames[last].hold = pauseEnd
+	if first == last {
+		frames[last].hold = pauseStart + pauseEnd
+	}
+}
+
+// textFrame returns the display data with the columns of the text starting at column x
+func (d *Display) textFrame(columns []uint16, x int, fontHeight int) []uint32 {
+	displayData := make([]uint32, d.Width())
+	d.placeColumns(displayData, columns, x, fontHeight)
+	return displayData
+}
+
+// horizontalFrames moves the text one column at a time.
+// The key frames are where the text first and last fills the display, or is shown whole if it is narrower.
+func (d *Display) horizontalFrames(columns []uint16, font fonts.Font, effect TextEffect, loop bool) []effectFrame {
+	width := d.Width()
+	length := len(columns)
+	// the text fills the display, or is shown whole, between these positions
+	leftmost, rightmost := min(0, width-length), max(0, width-length)
+
+	var positions []int
+	switch effect {
+	case EffectScrollRight:
+		for x := -length; x <= width; x++ {
+			positions = append(positions, x)
+		}
+	case EffectBounce:
+		for x := rightmost; x >= leftmost; x-- {
+			positions = append(positions, x)
+		}
+		for x := leftmost + 1; x < rightmost; x++ {
+			positions = append(positions, x)
+		}
+		// when looping the first position follows
+		if !loop && leftmost != rightmost {
+			positions = append(positions, rightmost)
+		}
+	default:
+		for x := width; x >= -length; x-- {
+			positions = append(positions, x)
+		}
+	}
+
+	frames := make([]effectFrame, len(positions))
+	for i, x := range positions {
+		frames[i] = effectFrame{displayData: d.textFrame(columns, x, font.Height())}
+	}
+	// only the first time the text passes the key positions, bounce comes back through them
+	for _, key := range []int{leftmost, rightmost} {
+		for i, x := range positions {
+			if x == key {
+				frames[i].key = true
+				break
+			}
+		}
+	}
+	return frames
+}
+
+// verticalFrames moves the lines of the text one row at a time, with a line the height of the display
+// in view at once. The key frames are where each line is in place.
+func (d *Display) verticalFrames(block textBlock, font fonts.Font, opts TextOptions) []effectFrame {
+	height := d.Height()
+
+	// every line is a page of the display between a blank page at the start and end
+	pages := make([][]uint32, len(block.lines))
+	for i, line := range block.lines {
+		x := d.alignX(opts.Align, len(line))
+		pages[i] = d.textFrame(line, x, font.Height())
+	}
+	if opts.Effect == EffectScrollDown {
+		// the first line comes in from the top, so it is at the bottom of the pages
+		for i, j := 0, len(pages)-1; i < j; i, j = i+1, j-1 {
+			pages[i], pages[j] = pages[j], pages[i]
+		}
+	}
+
+	var frames []effectFrame
+	mask := uint32(1)<<height - 1
+	for offset := 0; offset <= (len(pages)+1)*height; offset++ {
+		displayData := make([]uint32, d.Width())
+		for i, page := range pages {
+			// rows of the page from the top of the display, the first page starts below the display
+			top := (i+1)*height - offset
+			if top <= -height || top >= height {
+				continue
+			}
+			for col, column := range page {
+				if top < 0 {
+					displayData[col] |= column >> -top & mask
+				} else {
+					displayData[col] |= column << top & mask
+				}
+			}
+		}
+		frames = append(frames, effectFrame{displayData: displayData, key: offset%height == 0 && offset > 0 && offset <= len(pages)*height})
+	}
+
+	if opts.Effect == EffectScrollDown {
+		for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+			frames[i], frames[j] = frames[j], frames[i]
+		}
+	}
+	return frames
+}
+
+// typewriterFrames adds the characters of the text one at a time where they are when the whole text is shown.
+// Text wider than the display moves left to keep the last character in view.
+// The key frames are the first character and the whole text.
+func (d *Display) typewriterFrames(text string, font fonts.Font, align Align) ([]effectFrame, []fonts.Substitution, error) {
+	full, substitutions, err := d.renderText(text, font)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	x := max(d.alignX(align, len(full)), 0)
+
+	var frames []effectFrame
+	chars := []rune(text)
+	previous := 0
+	for i := 1; i <= len(chars); i++ {
+		columns, _, err := d.renderText(string(chars[:i]), font)
+		if err != nil {
+			return nil, nil, err
+		}
+		// spaces and skipped characters do not show anything new
+		if len(columns) == previous || strings.TrimSpace(string(chars[i-1])) == "" {
+			continue
+		}
+		previous = len(columns)
+		frames = append(frames, effectFrame{displayData: d.textFrame(columns, min(x, d.Width()-len(columns)), font.Height())})
+	}
+	if len(frames) > 0 {
+		frames[0].key = true
+		frames[len(frames)-1].key = true
+	}
+	return frames, substitutions, nil
+}
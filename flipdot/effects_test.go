@@ -0,0 +1,181 @@
+package flipdot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+func init() {
+	// characters one column wide, on rows 6 and 7 of the display when centered
+	fonts.Register(fonts.NewBitmapFont("test-effects", 2, 1, map[rune][]uint16{'a': {0b01}, 'b': {0b10}, ' ': {0}}))
+}
+
+// textColumns returns the first and last column with a dot that is on, -1 if the frame is blank
+func textColumns(frame []uint32) (int, int) {
+	first, last := -1, -1
+	for i, column := range frame {
+		if column != 0 {
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+	}
+	return first, last
+}
+
+func showEffect(t *testing.T, text string, opts TextOptions) [][]uint32 {
+	t.Helper()
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout()}
+	opts.Font = "test-effects"
+	if err := display.ShowTextEffect(context.Background(), text, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var frames [][]uint32
+	for _, call := range mock.Calls() {
+		frames = append(frames, call.DisplayData)
+	}
+	return frames
+}
+
+func TestTextEffectScroll(t *testing.T) {
+	// "ab" is 3 columns wide with the gap between the characters
+	frames := showEffect(t, "ab", TextOptions{Effect: EffectScrollLeft})
+	if len(frames) != 28+3+1 {
+		t.Fatalf("expected %d frames, got %d", 28+3+1, len(frames))
+	}
+	for i, frame := range frames {
+		first, _ := textColumns(frame)
+		if i > 0 && i < 28 && first != 28-i {
+			t.Errorf("frame %d: expected the text to start at column %d, got %d", i, 28-i, first)
+		}
+	}
+
+	frames = showEffect(t, "ab", TextOptions{Effect: EffectScrollRight})
+	if len(frames) != 28+3+1 {
+		t.Fatalf("expected %d frames, got %d", 28+3+1, len(frames))
+	}
+	// the end of the text comes in first
+	if first, _ := textColumns(frames[1]); first != 0 || frames[1][0] != 0b10<<6 {
+		t.Errorf("expected the 'b' in the first column, got %b", frames[1])
+	}
+	if _, last := textColumns(frames[30]); last != 27 {
+		t.Errorf("expected the text to end at the right edge, got column %d", last)
+	}
+}
+
+func TestTextEffectBounce(t *testing.T) {
+	// 15 characters and 14 gaps are 29 columns, one more than the display
+	frames := showEffect(t, "aaaaaaaaaaaaaaa", TextOptions{Effect: EffectBounce})
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	// the characters are on the even columns of the text, so they are on odd columns of the display
+	// when the text has moved one column to the left
+	for i, expected := range []int{0, 1, 0} {
+		if first, _ := textColumns(frames[i]); first != expected {
+			t.Errorf("frame %d: expected the first dot in column %d, got %d", i, expected, first)
+		}
+	}
+}
+
+func TestTextEffectTypewriter(t *testing.T) {
+	frames := showEffect(t, "a b", TextOptions{Effect: EffectTypewriter, Align: AlignLeft})
+	if len(frames) != 2 {
+		t.Fatalf("expected a frame for each character that is not a space, got %d", len(frames))
+	}
+	if frames[0][0] != 0b01<<6 || frames[0][2] != 0 {
+		t.Errorf("expected only the 'a' in the first frame, got %b", frames[0])
+	}
+	// the space is one column wide
+	if frames[1][0] != 0b01<<6 || frames[1][4] != 0b10<<6 {
+		t.Errorf("expected the whole text in the last frame, got %b", frames[1])
+	}
+}
+
+func TestTextEffectVertical(t *testing.T) {
+	// each line is shown in the middle column when it is in place
+	frames := showEffect(t, "a\nb", TextOptions{Effect: EffectScrollUp})
+	if len(frames) != 3*14+1 {
+		t.Fatalf("expected %d frames, got %d", 3*14+1, len(frames))
+	}
+	if frames[14][13] != 0b01<<6 || frames[28][13] != 0b10<<6 {
+		t.Errorf("expected the lines in place in frames 14 and 28, got %b and %b", frames[14][13], frames[28][13])
+	}
+	// halfway through the line has moved up from the bottom
+	if frames[7][13] != 0b01<<13 {
+		t.Errorf("expected the first line on the bottom row in frame 7, got %b", frames[7][13])
+	}
+
+	frames = showEffect(t, "a\nb", TextOptions{Effect: EffectScrollDown})
+	if frames[14][13] != 0b01<<6 || frames[28][13] != 0b10<<6 {
+		t.Errorf("expected the lines in place in frames 14 and 28, got %b and %b", frames[14][13], frames[28][13])
+	}
+	if frames[10][13] != 0b01<<2 {
+		t.Errorf("expected the first line on row 2 in frame 10, got %b", frames[10][13])
+	}
+}
+
+func TestTextEffectPauses(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+	font, err := fonts.Lookup("test-effects")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the 3 columns are first shown whole against the right edge and last against the left edge
+	frames := display.horizontalFrames([]uint16{1, 0, 2}, font, EffectScrollLeft, false)
+	holdKeyFrames(frames, time.Second, 2*time.Second)
+	for i, frame := range frames {
+		expected := time.Duration(0)
+		switch i {
+		case 3:
+			expected = time.Second
+		case 28:
+			expected = 2 * time.Second
+		}
+		if frame.hold != expected {
+			t.Errorf("frame %d: expected a hold of %v, got %v", i, expected, frame.hold)
+		}
+	}
+
+	start := time.Now()
+	showEffect(t, "ab", TextOptions{Effect: EffectTypewriter, PauseEnd: 50 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the whole text to be held for 50ms, took %v", elapsed)
+	}
+}
+
+// Test that looping text without any frames returns instead of looping forever
+func TestTextEffectNoFrames(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- display.ShowTextEffect(ctx, "   ", TextOptions{Font: "test-effects", Effect: EffectTypewriter, Loop: true})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected blank looping text to return")
+	}
+}
+
+func TestParseTextEffect(t *testing.T) {
+	effect, err := ParseTextEffect("scroll-up")
+	if err != nil || effect != EffectScrollUp {
+		t.Errorf("expected EffectScrollUp, got %v, %v", effect, err)
+	}
+	if _, err := ParseTextEffect("spin"); err == nil {
+		t.Errorf("expected an error for an unknown effect")
+	}
+}
@@ -0,0 +1,336 @@
+package flipdot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fonts "github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+// DefaultClockFace is the face the clock is shown with unless Config.ClockFace is set
+const DefaultClockFace = "digital"
+
+// ClockFace shows the time on a display. Faces are chosen by the name they are registered with,
+// see RegisterClockFace.
+type ClockFace interface {
+	// Name is the name the face is registered with
+	Name() string
+	// Check returns an error if the face can not be shown on the display with its clock settings
+	Check(d *Display) error
+	// Show shows the time t, which is in the clock zone with the label, empty if the zone has none.
+	// A face that moves keeps moving until ctx is done, which is when the clock is next updated.
+	Show(ctx context.Context, d *Display, t time.Time, label string) error
+	// Next returns when the face next changes after t
+	Next(d *Display, t time.Time) time.Time
+}
+
+var (
+	clockFacesMu sync.RWMutex
+	clockFaces   = map[string]ClockFace{}
+)
+
+func init() {
+	RegisterClockFace(digitalFace{})
+	RegisterClockFace(analogFace{})
+	RegisterClockFace(binaryFace{})
+	RegisterClockFace(fuzzyFace{})
+	RegisterClockFace(tallFace{})
+}
+
+// RegisterClockFace makes a clock face available by its name, the name must not be in use already
+func RegisterClockFace(face ClockFace) error {
+	clockFacesMu.Lock()
+	defer clockFacesMu.Unlock()
+
+	if _, ok := clockFaces[face.Name()]; ok {
+		return fmt.Errorf("clock face '%s' is already registered", face.Name())
+	}
+	clockFaces[face.Name()] = face
+	return nil
+}
+
+// LookupClockFace returns the clock face registered with the name
+func LookupClockFace(name string) (ClockFace, error) {
+	clockFacesMu.RLock()
+	face, ok := clockFaces[name]
+	clockFacesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("clock face '%s' not found, must be one of '%s'", name, strings.Join(ClockFaceNames(), "', '"))
+	}
+	return face, nil
+}
+
+// ClockFaceNames returns the names of all registered clock faces in alphabetical order
+func ClockFaceNames() []string {
+	clockFacesMu.RLock()
+	defer clockFacesMu.RUnlock()
+
+	var names []string
+	for name := range clockFaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fontFace is a clock face that is always shown in one font, NewDisplay makes it the clock font
+type fontFace interface {
+	ClockFace
+	clockFont() string
+}
+
+// face returns the clock face of the display, the digital face if it has none
+func (d *Display) face() ClockFace {
+	if d.clockFace == nil {
+		return digitalFace{}
+	}
+	return d.clockFace
+}
+
+// digitalFace shows the time as text in the clock format, see Display.clockFrame
+type digitalFace struct{}
+
+func (digitalFace) Name() string {
+	return "digital"
+}
+
+func (digitalFace) Check(d *Display) error {
+	return d.checkClockFormat()
+}
+
+func (digitalFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	displayData, err := d.clockFrame(t, label)
+	if err != nil {
+		return err
+	}
+	return d.Show(displayData)
+}
+
+func (digitalFace) Next(d *Display, t time.Time) time.Time {
+	return nextClockUpdate(t, d.clockInterval())
+}
+
+// tallFace is the digital face in the tall font, digits 14 pixels high that fill the height of the display
+type tallFace struct {
+	digitalFace
+}
+
+func (tallFace) Name() string {
+	return "tall"
+}
+
+func (tallFace) clockFont() string {
+	return tallClockFont
+}
+
+func (tallFace) Check(d *Display) error {
+	if d.timeFont() != tallClockFont {
+		return fmt.Errorf("the tall clock face is shown in the %s font, not the %s font", tallClockFont, d.timeFont())
+	}
+	return d.checkClockFont()
+}
+
+// analogFace shows a dial with 12 dots for the hours and hands for the hours and minutes,
+// the height of the display. The label is shown to the left of the dial.
+type analogFace struct{}
+
+func (analogFace) Name() string {
+	return "analog"
+}
+
+func (analogFace) Check(d *Display) error {
+	if d.Height() < 7 {
+		return fmt.Errorf("the analog clock needs a display at least 7 rows high, display is %d rows high", d.Height())
+	}
+	for _, zone := range d.clockZones {
+		if _, err := analogLabel(d, zone.Label); err != nil {
+			return fmt.Errorf("invalid clock zone label '%s': %v", zone.Label, err)
+		}
+	}
+	return nil
+}
+
+// analogRadius returns the radius of the dial, which has an odd size so its center is a dot
+func analogRadius(d *Display) int {
+	return (min(d.Width(), d.Height()) - 1) / 2
+}
+
+// analogLabel returns the columns of the label in the mini font, with an error if it does not fit next to the dial
+func analogLabel(d *Display, label string) ([]uint16, error) {
+	if label == "" {
+		return nil, nil
+	}
+	font, err := fonts.Lookup(clockDateFont)
+	if err != nil {
+		return nil, err
+	}
+	labelColumns, _, err := d.renderText(label, font)
+	if err != nil {
+		return nil, err
+	}
+	if len(labelColumns)+1+2*analogRadius(d)+1 > d.Width() {
+		return nil, fmt.Errorf("label '%s' does not fit next to the analog clock", label)
+	}
+	return labelColumns, nil
+}
+
+func (analogFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	radius := analogRadius(d)
+	size := 2*radius + 1
+
+	labelColumns, err := analogLabel(d, label)
+	if err != nil {
+		return err
+	}
+
+	frame := d.NewFrame()
+	cx, cy := (d.Width()-size)/2+radius, (d.Height()-size)/2+radius
+	if len(labelColumns) > 0 {
+		cx = d.Width() - 1 - radius
+	}
+
+	point := func(angle float64, length float64) (int, int) {
+		return cx + int(math.Round(length*math.Sin(angle))), cy - int(math.Round(length*math.Cos(angle)))
+	}
+	for hour := range 12 {
+		x, y := point(float64(hour)*math.Pi/6, float64(radius))
+		frame.SetPixel(x, y, true)
+	}
+
+	minutes := float64(t.Minute())
+	hours := float64(t.Hour()%12) + minutes/60
+	x, y := point(minutes*math.Pi/30, float64(radius)-1)
+	frame.Line(cx, cy, x, y, true)
+	x, y = point(hours*math.Pi/6, float64(radius)/2)
+	frame.Line(cx, cy, x, y, true)
+
+	displayData := frame.Columns()
+	if len(labelColumns) > 0 {
+		d.drawColumns(displayData, labelColumns, (cx-radius-len(labelColumns))/2, (d.Height()-5)/2)
+	}
+	return d.Show(displayData)
+}
+
+func (analogFace) Next(d *Display, t time.Time) time.Time {
+	return nextClockUpdate(t, time.Minute)
+}
+
+const (
+	// binaryDot is the size of the square dots of the binary clock
+	binaryDot = 2
+	// binaryBits are the bits of each digit of the binary clock
+	binaryBits = 4
+)
+
+// binaryFace shows the digits of the hours, minutes and seconds as columns of binary coded decimal bits,
+// the lowest bit at the bottom. The label is not shown.
+type binaryFace struct{}
+
+func (binaryFace) Name() string {
+	return "binary"
+}
+
+// binaryWidth returns the columns of the binary clock: 6 columns of dots with a blank column between
+// the digits of a pair and 3 between the pairs
+func binaryWidth() int {
+	return 6*binaryDot + 3*1 + 2*3
+}
+
+// binaryHeight returns the rows of the binary clock, the bits with a blank row between them
+func binaryHeight() int {
+	return binaryBits*binaryDot + binaryBits - 1
+}
+
+func (binaryFace) Check(d *Display) error {
+	if d.Width() < binaryWidth() || d.Height() < binaryHeight() {
+		return fmt.Errorf("the binary clock needs a display at least %dx%d, display is %dx%d", binaryWidth(), binaryHeight(), d.Width(), d.Height())
+	}
+	return nil
+}
+
+func (binaryFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	frame := d.NewFrame()
+	x := (d.Width() - binaryWidth()) / 2
+	bottom := (d.Height()+binaryHeight())/2 - binaryDot
+
+	for i, value := range []int{t.Hour(), t.Minute(), t.Second()} {
+		for _, digit := range []int{value / 10, value % 10} {
+			for bit := range binaryBits {
+				if digit&(1<<bit) != 0 {
+					frame.FillRect(x, bottom-bit*(binaryDot+1), binaryDot, binaryDot, true)
+				}
+			}
+			x += binaryDot + 1
+		}
+		if i < 2 {
+			// the gap between the pairs is 3 columns
+			x += 2
+		}
+	}
+	return d.ShowFrame(frame)
+}
+
+func (binaryFace) Next(d *Display, t time.Time) time.Time {
+	return nextClockUpdate(t, time.Second)
+}
+
+const (
+	// fuzzyRound is how close the fuzzy clock tells the time
+	fuzzyRound = 5 * time.Minute
+	// fuzzyFont is the font the fuzzy clock scrolls the time in
+	fuzzyFont = "small"
+)
+
+var hourWords = []string{"TWELVE", "ONE", "TWO", "THREE", "FOUR", "FIVE", "SIX", "SEVEN", "EIGHT", "NINE", "TEN", "ELEVEN"}
+
+// minuteWords are the words for every 5 minutes, the ones with TO are before the next hour
+var minuteWords = []string{"O'CLOCK", "FIVE PAST", "TEN PAST", "QUARTER PAST", "TWENTY PAST", "TWENTY FIVE PAST", "HALF PAST",
+	"TWENTY FIVE TO", "TWENTY TO", "QUARTER TO", "TEN TO", "FIVE TO"}
+
+// fuzzyFace scrolls the time in words to the nearest 5 minutes, e.g. "TEN PAST THREE", after the label
+type fuzzyFace struct{}
+
+func (fuzzyFace) Name() string {
+	return "fuzzy"
+}
+
+// Check checks that the labels can be written in the font of the fuzzy clock, the time in words always can
+func (fuzzyFace) Check(d *Display) error {
+	for _, zone := range d.clockZones {
+		if _, err := d.CheckText(zone.Label, fuzzyFont); err != nil {
+			return fmt.Errorf("invalid clock zone label '%s': %v", zone.Label, err)
+		}
+	}
+	return nil
+}
+
+// fuzzyTime returns the time in words to the nearest 5 minutes
+func fuzzyTime(t time.Time) string {
+	rounded := t.Add(fuzzyRound / 2).Truncate(fuzzyRound)
+	i := rounded.Minute() / 5
+	hour := rounded.Hour()
+	if strings.HasSuffix(minuteWords[i], " TO") {
+		hour++
+	}
+	hourWord := hourWords[hour%12]
+	if i == 0 {
+		return hourWord + " " + minuteWords[i]
+	}
+	return minuteWords[i] + " " + hourWord
+}
+
+func (fuzzyFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	text := strings.TrimSpace(label + " " + fuzzyTime(t))
+	return d.ShowTextEffect(ctx, text, TextOptions{Font: fuzzyFont, Speed: DefaultColumnsPerSecond, Loop: true})
+}
+
+// Next returns when the time in words changes, half way between the 5 minutes
+func (fuzzyFace) Next(d *Display, t time.Time) time.Time {
+	return nextClockUpdate(t.Add(fuzzyRound/2), fuzzyRound).Add(-fuzzyRound / 2)
+}
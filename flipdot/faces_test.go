@@ -0,0 +1,246 @@
+package flipdot
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	fonts "github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+func init() {
+	RegisterClockFace(testFace{})
+}
+
+// testFace shows every column with the minute as its bits
+type testFace struct{}
+
+func (testFace) Name() string {
+	return "test-face"
+}
+
+func (testFace) Check(d *Display) error {
+	return nil
+}
+
+func (testFace) Show(ctx context.Context, d *Display, t time.Time, label string) error {
+	displayData := make([]uint32, d.Width())
+	for i := range displayData {
+		displayData[i] = uint32(t.Minute())
+	}
+	return d.Show(displayData)
+}
+
+func (testFace) Next(d *Display, t time.Time) time.Time {
+	return nextClockUpdate(t, time.Minute)
+}
+
+func TestClockFaceRegistry(t *testing.T) {
+	for _, name := range []string{"analog", "binary", "digital", "fuzzy", "tall"} {
+		if !slices.Contains(ClockFaceNames(), name) {
+			t.Errorf("expected the %s face to be registered, got %v", name, ClockFaceNames())
+		}
+	}
+	if _, err := LookupClockFace("cuckoo"); err == nil {
+		t.Error("expected error for an unknown face")
+	}
+	if err := RegisterClockFace(digitalFace{}); err == nil {
+		t.Error("expected error for a face that is already registered")
+	}
+
+	// a registered face can be chosen by name
+	display, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockFace: "test-face"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer display.Close()
+	mock := &MockDisplayOutput{}
+	display.output = mock
+	runFakeClock(t, display, &fakeClock{now: time.Date(2024, time.March, 4, 10, 42, 30, 0, time.UTC), max: 1})
+	if calls := mock.Calls(); len(calls) != 1 || calls[0].DisplayData[0] != 42 {
+		t.Errorf("expected the test face to show the minute, got %v", calls)
+	}
+}
+
+func showFace(t *testing.T, face ClockFace, now time.Time, label string) *Frame {
+	t.Helper()
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout(), clockFace: face}
+	if label != "" {
+		display.clockZones = []ClockZone{{Label: label, Location: now.Location()}}
+	}
+	if err := face.Check(display); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := display.showTime(context.Background(), now, face.Next(display, now)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 Show call, got %d", len(calls))
+	}
+	return FrameFromColumns(calls[0].DisplayData, display.Height())
+}
+
+func TestAnalogFace(t *testing.T) {
+	// the dial is 13 dots across with its center at column 13 and row 6
+	frame := showFace(t, analogFace{}, time.Date(2024, time.March, 4, 15, 0, 0, 0, time.UTC), "")
+
+	for y := 1; y <= 5; y++ {
+		if !frame.GetPixel(13, y) {
+			t.Errorf("expected the minute hand pointing up through row %d", y)
+		}
+	}
+	for x := 14; x <= 16; x++ {
+		if !frame.GetPixel(x, 6) {
+			t.Errorf("expected the hour hand pointing right through column %d", x)
+		}
+	}
+	if frame.GetPixel(13, 7) || frame.GetPixel(12, 6) {
+		t.Error("expected no hands pointing down or left")
+	}
+	// the dots for 12, 3, 6 and 9
+	for _, dot := range [][2]int{{13, 0}, {19, 6}, {13, 12}, {7, 6}} {
+		if !frame.GetPixel(dot[0], dot[1]) {
+			t.Errorf("expected a dot of the dial at %v", dot)
+		}
+	}
+
+	// with a label the dial moves to the right edge
+	frame = showFace(t, analogFace{}, time.Date(2024, time.March, 4, 15, 0, 0, 0, time.UTC), "NYC")
+	if !frame.GetPixel(21, 0) || frame.GetPixel(13, 0) {
+		t.Error("expected the dial at the right edge")
+	}
+}
+
+// Test that the analog face rejects clock zone labels that do not fit next to the dial before the clock runs
+func TestAnalogFaceCheckLabels(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+	display.clockZones = []ClockZone{{Label: "NYC", Location: time.UTC}, {Label: "BERLIN", Location: time.UTC}}
+	if err := (analogFace{}).Check(display); err == nil {
+		t.Error("expected error for a label wider than the space next to the dial")
+	}
+	display.clockZones = []ClockZone{{Label: "NYC", Location: time.UTC}, {Label: "ZÜR", Location: time.UTC}}
+	if err := (analogFace{}).Check(display); err == nil {
+		t.Error("expected error for a label with a character the mini font does not have")
+	}
+	display.textFallback = fonts.Fallback{StripAccents: true}
+	if err := (analogFace{}).Check(display); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTallFace(t *testing.T) {
+	// the display gets the tall font from the face
+	display, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockFace: "tall"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer display.Close()
+	mock := &MockDisplayOutput{}
+	display.output = mock
+	now := time.Date(2024, time.March, 4, 20, 28, 0, 0, time.UTC)
+	if err := display.showTime(context.Background(), now, now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := FrameFromColumns(mock.Calls()[0].DisplayData, display.Height())
+
+	// the time fills every row, with a blank column on each side
+	for y := range frame.Height() {
+		found := false
+		for x := range frame.Width() {
+			found = found || frame.GetPixel(x, y)
+		}
+		if !found {
+			t.Errorf("expected row %d to be used", y)
+		}
+	}
+	for y := range frame.Height() {
+		if frame.GetPixel(0, y) || frame.GetPixel(frame.Width()-1, y) {
+			t.Fatalf("expected the first and last columns to be blank")
+		}
+	}
+
+	if _, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockFace: "tall", ClockFont: "small"}); err == nil {
+		t.Error("expected error for another clock font")
+	}
+	if _, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockFace: "tall", ClockFormat: "15:04:05"}); err == nil {
+		t.Error("expected error for seconds")
+	}
+	// Check does not choose the font
+	if err := (tallFace{}).Check(&Display{layout: DefaultLayout()}); err == nil {
+		t.Error("expected error for a display without the tall font")
+	}
+}
+
+func TestBinaryFace(t *testing.T) {
+	frame := showFace(t, binaryFace{}, time.Date(2024, time.March, 4, 12, 34, 56, 0, time.UTC), "")
+
+	// the digits 1 2 3 4 5 6 in columns of 2x2 dots starting at column 3, the lowest bit on rows 10 and 11
+	x := []int{3, 6, 11, 14, 19, 22}
+	for i, digit := range []int{1, 2, 3, 4, 5, 6} {
+		for bit := range 4 {
+			expected := digit&(1<<bit) != 0
+			y := 10 - bit*3
+			if on := frame.GetPixel(x[i], y) && frame.GetPixel(x[i]+1, y+1); on != expected {
+				t.Errorf("expected bit %d of digit %d to be %v", bit, digit, expected)
+			}
+		}
+	}
+}
+
+func TestFuzzyTime(t *testing.T) {
+	testCases := []struct {
+		hour, minute int
+		expected     string
+	}{
+		{hour: 15, minute: 0, expected: "THREE O'CLOCK"},
+		{hour: 15, minute: 8, expected: "TEN PAST THREE"},
+		{hour: 15, minute: 13, expected: "QUARTER PAST THREE"},
+		{hour: 15, minute: 30, expected: "HALF PAST THREE"},
+		{hour: 15, minute: 40, expected: "TWENTY TO FOUR"},
+		{hour: 23, minute: 58, expected: "TWELVE O'CLOCK"},
+		{hour: 0, minute: 5, expected: "FIVE PAST TWELVE"},
+		{hour: 11, minute: 33, expected: "TWENTY FIVE TO TWELVE"},
+	}
+	for _, tc := range testCases {
+		now := time.Date(2024, time.March, 4, tc.hour, tc.minute, 0, 0, time.UTC)
+		if words := fuzzyTime(now); words != tc.expected {
+			t.Errorf("expected '%s' at %s, got '%s'", tc.expected, now.Format("15:04"), words)
+		}
+	}
+
+	// the words change half way between the 5 minutes
+	next := fuzzyFace{}.Next(nil, time.Date(2024, time.March, 4, 15, 8, 0, 0, time.UTC))
+	if !next.Equal(time.Date(2024, time.March, 4, 15, 12, 30, 0, time.UTC)) {
+		t.Errorf("expected the next change at 15:12:30, got %v", next)
+	}
+}
+
+// Test that the fuzzy face checks the labels in its font with the display's fallback
+func TestFuzzyFaceCheckLabels(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+	display.clockZones = []ClockZone{{Label: "Zürich", Location: time.UTC}}
+	if err := (fuzzyFace{}).Check(display); err == nil {
+		t.Error("expected error for a label with a character the small font does not have")
+	}
+	display.textFallback = fonts.Fallback{StripAccents: true}
+	if err := (fuzzyFace{}).Check(display); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// Test that the fuzzy clock scrolls until the next update
+func TestFuzzyFaceScrolls(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout(), clockFace: fuzzyFace{}}
+	now := time.Date(2024, time.March, 4, 15, 8, 0, 0, time.UTC)
+
+	if err := display.showTime(context.Background(), now, now.Add(200*time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls := len(mock.Calls()); calls < 2 {
+		t.Errorf("expected the words to scroll, got %d Show calls", calls)
+	}
+}
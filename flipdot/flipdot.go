@@ -1,8 +1,10 @@
 package flipdot
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	fonts "github.com/FutureSharks/flipdot-clock/flipdot/fonts"
@@ -11,38 +13,235 @@ import (
 	"go.bug.st/serial"
 )
 
+// DefaultClockFormat is the time layout used by the clock unless Config.ClockFormat is set
+const DefaultClockFormat = "15:04"
+
+const (
+	// defaultClockFont is the font the clock is shown in unless Config.ClockFont is set
+	defaultClockFont = "small"
+	// condensedClockFont is used for clock formats that do not fit in the default font, e.g. with seconds
+	condensedClockFont = "condensed"
+	// tallClockFont is the font of the tall clock face, digits the height of the display
+	tallClockFont = "tall"
+	// clockDateFont is the font of the second row of the clock
+	clockDateFont = "mini"
+)
+
 // DisplayOutput interface for different output methods
+// displayData has one value per column of the sign, the lowest bit is the top row
 type DisplayOutput interface {
-	Show(displayData [28]uint16) error
+	Show(displayData []uint32) error
 	Close() error
 }
 
 // Display represents a flipdot display
-// it could be a physical Alfa-Zeta 14*28 display connected via serial port or a simulated display that runs in the terminal
+// it could be a physical Alfa-Zeta display made up of one or more panels connected via serial port or the network, or a simulated display that runs in the terminal or browser
 type Display struct {
-	output DisplayOutput
+	output          DisplayOutput
+	layout          Layout
+	clockFormat     string
+	clockFont       string
+	clockDate       string
+	clockPMDot      bool
+	clockSecondsBar bool
+	clockZones      []ClockZone
+	clockFace       ClockFace
+	clockSchedule   Schedule
+	clock           Clock
+	textFallback    fonts.Fallback
+}
+
+// Config holds the settings for creating a Display
+type Config struct {
+	// Terminal also displays output in the terminal, the same as adding "terminal" to Outputs
+	Terminal bool
+	// Outputs is where the display data is sent, every frame goes to all of them:
+	// "serial" (the default), "terminal",
+	// "udp://host:port[,host:port...]" for panels with LAN controllers,
+	// "tcp://host:port" for a serial to Ethernet converter,
+	// "web://[host]:port" for a simulator in the browser or
+	// "gif://file.gif" and "png://directory" to record the frames as images.
+	// An output can end with "?on-error=fail", "?on-error=log" or "?on-error=disable" to decide
+	// what happens when it fails, the default is to fail.
+	Outputs    []string
+	SerialPort string
+	BaudRate   int
+	// Buffered sends all panels before showing them at once, which avoids tearing between panels
+	Buffered bool
+	// FullRefreshInterval is how often the serial output sends panels that have not changed, to correct stuck dots.
+	// Zero means unchanged panels are never sent again.
+	FullRefreshInterval time.Duration
+	// Layout of the panels making up the sign, DefaultLayout is used if it has no panels
+	Layout Layout
+	// ClockFormat is the Go time layout or strftime format of the clock, e.g. "15:04" or "%I:%M", see ClockLayout.
+	// DefaultClockFormat is used if empty.
+	ClockFormat string
+	// ClockFont is the name of the font the clock is shown in. If empty the small font is used, or the condensed
+	// font if the clock does not fit in it.
+	ClockFont string
+	// ClockDate is the format of a second row under the time in the mini font, e.g. "Mon 2" or "%a %-d".
+	// Empty shows only the time.
+	ClockDate string
+	// ClockPMDot shows a dot in the top left corner in the afternoon, for 12 hour formats like "3:04"
+	ClockPMDot bool
+	// ClockSecondsBar shows the seconds as a bar along the bottom row that fills the display every minute
+	ClockSecondsBar bool
+	// ClockZones are the time zones the clock shows in turn, with their labels before the date.
+	// Empty shows the local time.
+	ClockZones []ClockZone
+	// ClockFace is the name of the clock face the time is shown with, see RegisterClockFace.
+	// DefaultClockFace is used if empty.
+	ClockFace string
+	// ClockSchedule is when the clock is shown, the display is blank outside it. Empty means always.
+	ClockSchedule Schedule
+	// Clock is where the clock gets the time from, the system time if nil
+	Clock Clock
+	// TextFallback is what is shown for characters that are not in the font of the text.
+	// The zero value makes them an error.
+	TextFallback fonts.Fallback
+}
+
+func NewDisplay(config Config) (*Display, error) {
+	layout := config.Layout
+	if len(layout.Panels) == 0 {
+		layout = DefaultLayout()
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid layout: %v", err)
+	}
+
+	clockFormat, err := ClockLayout(config.ClockFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clock format: %v", err)
+	}
+	clockDate, err := ClockLayout(config.ClockDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clock date: %v", err)
+	}
+
+	for _, zone := range config.ClockZones {
+		if zone.Location == nil {
+			return nil, fmt.Errorf("invalid clock zone '%s': time zone missing", zone.Label)
+		}
+	}
+
+	faceName := config.ClockFace
+	if faceName == "" {
+		faceName = DefaultClockFace
+	}
+	face, err := LookupClockFace(faceName)
+	if err != nil {
+		return nil, err
+	}
+	clockFont := config.ClockFont
+	if f, ok := face.(fontFace); ok {
+		if clockFont != "" && clockFont != f.clockFont() {
+			return nil, fmt.Errorf("the %s clock face is shown in the %s font, not the %s font", face.Name(), f.clockFont(), clockFont)
+		}
+		clockFont = f.clockFont()
+	}
+
+	display := &Display{
+		layout:          layout,
+		clockFormat:     clockFormat,
+		clockFont:       clockFont,
+		clockDate:       clockDate,
+		clockPMDot:      config.ClockPMDot,
+		clockSecondsBar: config.ClockSecondsBar,
+		clockZones:      config.ClockZones,
+		clockFace:       face,
+		clockSchedule:   config.ClockSchedule,
+		clock:           config.Clock,
+		textFallback:    config.TextFallback,
+	}
+	if err := face.Check(display); err != nil {
+		return nil, err
+	}
+
+	output, err := newOutputs(config, layout)
+	if err != nil {
+		return nil, err
+	}
+	display.output = output
+	return display, nil
+}
+
+// newOutputs creates the DisplayOutput for config.Outputs
+// A single output is used on its own, several are combined in a MultiOutput
+func newOutputs(config Config, layout Layout) (DisplayOutput, error) {
+	specs := append([]string(nil), config.Outputs...)
+	if config.Terminal {
+		specs = append(specs, "terminal")
+	}
+	if len(specs) == 0 {
+		specs = []string{"serial"}
+	}
+
+	if len(specs) == 1 && !strings.Contains(specs[0], errorPolicySeparator) {
+		return newOutput(specs[0], config, layout)
+	}
+
+	multi := NewMultiOutput()
+	for _, s := range specs {
+		spec, policy, err := splitErrorPolicy(s)
+		if err != nil {
+			multi.Close()
+			return nil, err
+		}
+		output, err := newOutput(spec, config, layout)
+		if err != nil {
+			multi.Close()
+			return nil, err
+		}
+		multi.Add(spec, output, policy)
+	}
+	return multi, nil
 }
 
-func NewDisplay(terminalMode bool, portName string, baudRate int) (*Display, error) {
-	if terminalMode {
-		return &Display{output: &TerminalOutput{}}, nil
+// newOutput creates the DisplayOutput described by spec, see Config.Outputs
+func newOutput(spec string, config Config, layout Layout) (DisplayOutput, error) {
+	switch {
+	case spec == "terminal":
+		return &TerminalOutput{height: layout.Height()}, nil
+	case strings.HasPrefix(spec, "web://"):
+		return newWebOutput(strings.TrimPrefix(spec, "web://"), layout)
+	case strings.HasPrefix(spec, "gif://"):
+		return NewGIFOutput(strings.TrimPrefix(spec, "gif://"), layout), nil
+	case strings.HasPrefix(spec, "png://"):
+		return NewPNGOutput(strings.TrimPrefix(spec, "png://"), layout)
 	}
-	return newSerialDisplay(portName, baudRate)
+
+	// the remaining outputs send data to real panels so every panel needs to be a known module type
+	for _, p := range layout.Panels {
+		if _, err := moduleForPanel(p); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case spec == "" || spec == "serial":
+		return newSerialOutput(config, layout)
+	case strings.HasPrefix(spec, "udp://"):
+		return NewUDPOutput(strings.Split(strings.TrimPrefix(spec, "udp://"), ","), layout)
+	case strings.HasPrefix(spec, "tcp://"):
+		return newTCPOutput(strings.TrimPrefix(spec, "tcp://"), config, layout)
+	}
+
+	return nil, fmt.Errorf("output '%s' not supported, must be one of 'serial', 'terminal', 'udp://host:port', 'tcp://host:port', 'web://[host]:port', 'gif://file.gif' or 'png://directory'", spec)
 }
 
-// NewSerialDisplay creates a new Display instance with serial output
-func newSerialDisplay(portName string, baudRate int) (*Display, error) {
+// newSerialOutput opens the serial port connected to the displays
+func newSerialOutput(config Config, layout Layout) (*SerialOutput, error) {
 	mode := &serial.Mode{
-		BaudRate: baudRate,
+		BaudRate: config.BaudRate,
 	}
 
-	port, err := serial.Open(portName, mode)
+	port, err := serial.Open(config.SerialPort, mode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open port: %v", err)
 	}
 
-	output := &SerialOutput{port: port}
-	return &Display{output: output}, nil
+	return newSerialOutputWithPort(port, config, layout), nil
 }
 
 // Close closes the display connection
@@ -50,131 +249,400 @@ func (d *Display) Close() error {
 	return d.output.Close()
 }
 
+// Width returns the width of the display in dots
+func (d *Display) Width() int {
+	return d.layout.Width()
+}
+
+// Height returns the height of the display in dots
+func (d *Display) Height() int {
+	return d.layout.Height()
+}
+
+// Clear turns off every dot of the display
+func (d *Display) Clear() error {
+	return d.Show(make([]uint32, d.Width()))
+}
+
 func (d *Display) RunTestPattern() error {
-	log.Debug("Running test pattern...")
+	return d.RunTestPatternContext(context.Background())
+}
 
-	centerX := 13.5
-	centerY := 6.5
+// RunTestPatternContext runs the test pattern until it finishes or ctx is cancelled
+func (d *Display) RunTestPatternContext(ctx context.Context) error {
+	log.Debug("Running test pattern...")
 
-	for i := 0; i < 16; i++ {
-		var displayData [28]uint16
-		for col := 0; col < 28; col++ {
-			var columnData uint16
-			for row := 0; row < 14; row++ {
+	width := d.Width()
+	height := d.Height()
+	centerX := float64(width-1) / 2
+	centerY := float64(height-1) / 2
+	// enough circles to expand past the corners and finish with a blank display
+	circles := int(math.Hypot(centerX, centerY)) + 2
+
+	for i := 0; i < circles; i++ {
+		frame := d.NewFrame()
+		for col := 0; col < width; col++ {
+			for row := 0; row < height; row++ {
 				dx := float64(col) - centerX
 				dy := float64(row) - centerY
 				distance := math.Sqrt(dx*dx + dy*dy)
-				if int(distance) == i {
-					columnData |= (1 << row)
-				}
+				frame.SetPixel(col, row, int(distance) == i)
 			}
-			displayData[col] = columnData
 		}
 
-		err := d.Show(displayData)
+		err := d.ShowFrame(frame)
 		if err != nil {
 			return fmt.Errorf("failed to send test pattern: %v", err)
 		}
-		time.Sleep(50 * time.Millisecond)
+		if err := sleep(ctx, 50*time.Millisecond); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// ShowTime displays the current time on the 14x28 display.
+// ShowTime displays the current time with the clock face.
+// A face that moves, like the fuzzy clock, keeps moving until the time it shows changes.
 func (d *Display) ShowTime() error {
-	now := time.Now()
-	timeStr := now.Format("15:04")
-	displayData := [28]uint16{}
+	now := d.timeSource().Now()
+	return d.showTime(context.Background(), now, d.nextClockTime(now))
+}
 
-	result := []uint16{}
-	for _, char := range timeStr {
-		fontData, err := fonts.GetCharacter(char, "small")
+// showTime displays the time now in the clock zone shown now, a face that moves until next
+func (d *Display) showTime(ctx context.Context, now time.Time, next time.Time) error {
+	zone, _ := d.clockZoneAt(now)
+	now = now.In(zone.Location)
+
+	log.Debugf("Displaying time: %s %s", now.Format(d.timeLayout()), zone.Label)
+
+	faceCtx, cancel := context.WithTimeout(ctx, next.Sub(now))
+	defer cancel()
+	err := d.face().Show(faceCtx, d, now, zone.Label)
+	if faceCtx.Err() != nil && ctx.Err() == nil {
+		// the face was stopped for the next update
+		return nil
+	}
+	return err
+}
+
+// clockFrame returns the display data of the clock at t. The time is centered on the display, or with a second
+// row of the label and date the rows of both down to their fonts' baselines are centered with a blank row
+// between them. The seconds bar takes the bottom row away from the text.
+func (d *Display) clockFrame(t time.Time, label string) ([]uint32, error) {
+	font, err := fonts.Lookup(d.timeFont())
+	if err != nil {
+		return nil, err
+	}
+	timeStr := t.Format(d.timeLayout())
+	timeColumns, err := d.prepareTime(timeStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(timeColumns) > d.Width() {
+		return nil, fmt.Errorf("'%s' is %d columns wide, display is %d columns wide", timeStr, len(timeColumns), d.Width())
+	}
+
+	rows := d.Height()
+	if d.clockSecondsBar {
+		rows--
+	}
+
+	displayData := make([]uint32, d.Width())
+	dateStr := strings.TrimSpace(label + " " + t.Format(d.clockDate))
+	if dateStr == "" {
+		d.placeColumns(displayData, timeColumns, (d.Width()-len(timeColumns))/2, font.Height())
+	} else {
+		dateFont, err := fonts.Lookup(clockDateFont)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		dateColumns, _, err := d.renderText(dateStr, dateFont)
+		if err != nil {
+			return nil, err
+		}
+		if len(dateColumns) > d.Width() {
+			return nil, fmt.Errorf("date '%s' is %d columns wide, display is %d columns wide", dateStr, len(dateColumns), d.Width())
 		}
-		// add the character
-		result = append(result, fontData...)
-		// add a small gap before next character
-		result = append(result, uint16(0))
-	}
 
-	// add left display border
-	displayData[0] = 0
+		timeHeight, dateHeight := font.Baseline()+1, dateFont.Baseline()+1
+		if timeHeight+1+dateHeight > rows {
+			return nil, fmt.Errorf("the time and date are %d rows high, %d rows are free", timeHeight+1+dateHeight, rows)
+		}
+		y := (rows - timeHeight - 1 - dateHeight) / 2
+		d.drawColumns(displayData, timeColumns, (d.Width()-len(timeColumns))/2, y)
+		d.drawColumns(displayData, dateColumns, (d.Width()-len(dateColumns))/2, y+timeHeight+1)
+	}
 
-	for i, v := range result {
-		displayData[i+1] = v
+	if d.clockSecondsBar {
+		bottom := uint32(1) << (d.Height() - 1)
+		for _, column := range displayData {
+			if column&bottom != 0 {
+				return nil, fmt.Errorf("'%s' uses the bottom row, which the seconds bar needs", timeStr)
+			}
+		}
+		for i := range (t.Second() + 1) * d.Width() / 60 {
+			displayData[i] |= bottom
+		}
+	}
+	if d.clockPMDot {
+		if displayData[0]&1 != 0 {
+			return nil, fmt.Errorf("'%s' uses the top left corner, which the PM dot needs", timeStr)
+		}
+		if t.Hour() >= 12 {
+			displayData[0] |= 1
+		}
 	}
+	return displayData, nil
+}
 
-	log.Debugf("Displaying time: %s", timeStr)
+// prepareTime returns the columns of the time in the clock font, with the font's spacing between the characters
+func (d *Display) prepareTime(timeStr string) ([]uint16, error) {
+	font, err := fonts.Lookup(d.timeFont())
+	if err != nil {
+		return nil, err
+	}
+	result, _, err := d.renderText(timeStr, font)
+	return result, err
+}
 
-	return d.Show(displayData)
+// timeFont returns the name of the font the clock is shown in
+func (d *Display) timeFont() string {
+	if d.clockFont == "" {
+		return defaultClockFont
+	}
+	return d.clockFont
 }
 
-func (d *Display) Show(displayData [28]uint16) error {
-	return d.output.Show(displayData)
+// timeLayout returns the layout the clock is formatted with
+func (d *Display) timeLayout() string {
+	if d.clockFormat == "" {
+		return DefaultClockFormat
+	}
+	return d.clockFormat
 }
 
-func (d *Display) ShowText(text string, scrollSpeed time.Duration, loop bool, fontSize string) error {
-	for {
-		allFrames := []uint16{}
+// checkClockFormat checks that the clock format can be shown on the display.
+// Without a clock font the condensed font is chosen if the clock does not fit in the default font.
+func (d *Display) checkClockFormat() error {
+	if d.clockFont != "" {
+		return d.checkClockFont()
+	}
 
-		// start with a blank display
-		for range 28 {
-			allFrames = append(allFrames, 0)
-		}
+	d.clockFont = defaultClockFont
+	err := d.checkClockFont()
+	if err == nil {
+		return nil
+	}
+	d.clockFont = condensedClockFont
+	if d.checkClockFont() == nil {
+		log.Infof("Clock format '%s' does not fit in the %s font, using the %s font", d.timeLayout(), defaultClockFont, condensedClockFont)
+		return nil
+	}
+	return err
+}
 
-		t, err := d.prepareText(text, fontSize)
-		if err != nil {
-			return err
+// checkClockFont checks that the clock fits on the display in the clock font
+func (d *Display) checkClockFont() error {
+	// a time with wide digits and the longest month and weekday names, in the afternoon for formats with AM/PM
+	sample := time.Date(2006, time.September, 27, 20, 28, 58, 0, time.UTC)
+	if _, err := d.clockFrame(sample, ""); err != nil {
+		return fmt.Errorf("invalid clock format '%s': %v", d.timeLayout(), err)
+	}
+	for _, zone := range d.clockZones {
+		if _, err := d.clockFrame(sample, zone.Label); err != nil {
+			return fmt.Errorf("invalid clock zone label '%s': %v", zone.Label, err)
 		}
-		allFrames = append(allFrames, t...)
-		allFrames = append(allFrames, 0)
+	}
+	return nil
+}
 
-		for {
-			toSend := [28]uint16{}
+// clockInterval returns how often the clock changes, every second if it shows seconds
+func (d *Display) clockInterval() time.Duration {
+	t := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	next := t.Add(time.Second)
+	if d.clockSecondsBar || t.Format(d.timeLayout()) != next.Format(d.timeLayout()) || t.Format(d.clockDate) != next.Format(d.clockDate) {
+		return time.Second
+	}
+	return time.Minute
+}
 
-			for i := range 28 {
-				v := uint16(0)
-				if i < len(allFrames) {
-					v = allFrames[i]
-				}
-				toSend[i] = v
-			}
+func (d *Display) Show(displayData []uint32) error {
+	if len(displayData) != d.Width() {
+		return fmt.Errorf("display data has %d columns, display is %d columns wide", len(displayData), d.Width())
+	}
+	return d.output.Show(displayData)
+}
 
-			err := d.Show(toSend)
-			if err != nil {
-				return err
-			}
+// NewFrame creates a blank frame the size of the display
+func (d *Display) NewFrame() *Frame {
+	return NewFrame(d.Width(), d.Height())
+}
 
-			// delete first column to scroll the display right to left
-			allFrames = allFrames[1:]
+// ShowFrame shows a frame on the display, it must be the same size as the display
+func (d *Display) ShowFrame(frame *Frame) error {
+	if frame.Width() != d.Width() || frame.Height() != d.Height() {
+		return fmt.Errorf("frame is %dx%d, display is %dx%d", frame.Width(), frame.Height(), d.Width(), d.Height())
+	}
+	return d.Show(frame.Columns())
+}
 
-			if len(allFrames) == 0 {
-				break
-			}
+// DefaultColumnsPerSecond is the speed text moves at unless TextOptions.Speed is set, about the speed of the old
+// scroll speed 5
+const DefaultColumnsPerSecond = 11
 
-			time.Sleep(scrollSpeed)
-		}
+// ScrollInterval returns the time between text scroll steps for a speed between 1 (slow) and 9 (fast)
+//
+// Deprecated: the 1 to 9 scale is only kept for old configurations, text speeds are in columns per second.
+func ScrollInterval(speed int) time.Duration {
+	return time.Duration(190-(speed*20)) * time.Millisecond
+}
 
-		if !loop {
-			break
-		}
+// ScrollSpeed returns the columns per second of a speed between 1 (slow) and 9 (fast), see ScrollInterval.
+// 1 is 6 columns per second, 5 is 11 and 9 is 100.
+//
+// Deprecated: the 1 to 9 scale is only kept for old configurations, text speeds are in columns per second.
+func ScrollSpeed(speed int) float64 {
+	return intervalSpeed(ScrollInterval(speed))
+}
+
+// intervalSpeed returns the steps per second with interval between them, 0 for no interval
+func intervalSpeed(interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
 	}
+	return float64(time.Second) / float64(interval)
+}
 
-	return nil
+func (d *Display) ShowText(text string, scrollSpeed time.Duration, loop bool, fontSize string) error {
+	return d.ShowTextContext(context.Background(), text, scrollSpeed, loop, fontSize)
+}
+
+// ShowTextContext scrolls text across the display from right to left until it finishes or ctx is cancelled
+func (d *Display) ShowTextContext(ctx context.Context, text string, scrollSpeed time.Duration, loop bool, fontSize string) error {
+	return d.ShowTextEffect(ctx, text, TextOptions{Font: fontSize, Speed: intervalSpeed(scrollSpeed), Loop: loop})
 }
 
+// prepareText returns the columns of the text in the font registered as fontSize, with the font's spacing
+// between the characters and a gap after the last one
 func (d *Display) prepareText(text string, fontSize string) ([]uint16, error) {
+	font, err := fonts.Lookup(fontSize)
+	if err != nil {
+		return nil, err
+	}
+	result, _, err := d.renderText(text, font)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, make([]uint16, fonts.SpacingOf(font.Name()).Letter)...), nil
+}
+
+// CheckText returns the characters of the text that are not in the font and what is shown instead,
+// or an error if the text can not be shown
+func (d *Display) CheckText(text string, fontSize string) ([]fonts.Substitution, error) {
+	font, err := fonts.Lookup(fontSize)
+	if err != nil {
+		return nil, err
+	}
+	_, substitutions, err := d.renderText(text, font)
+	return substitutions, err
+}
+
+// renderText returns the columns of the text with the font's spacing between the characters, and the characters
+// that were substituted by the display's text fallback
+func (d *Display) renderText(text string, font fonts.Font) ([]uint16, []fonts.Substitution, error) {
+	spacing := fonts.SpacingOf(font.Name())
 	result := []uint16{}
+	var substitutions []fonts.Substitution
+	var previous rune
 	for _, char := range text {
-		letterData, err := fonts.GetCharacter(char, fontSize)
+		letterData, substitution, err := d.textFallback.Glyph(font, char)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if substitution != nil {
+			substitutions = append(substitutions, *substitution)
+			if substitution.With == "" {
+				// the character is skipped, so it does not get a gap either
+				continue
+			}
+		}
+		if len(result) == 0 {
+			result = append(result, letterData...)
+		} else {
+			result = appendGlyph(result, letterData, spacing.Between(previous, char))
 		}
-		result = append(result, letterData...)
-		result = append(result, uint16(0))
+		previous = char
 	}
 
-	return result, nil
+	return result, substitutions, nil
+}
+
+// appendGlyph adds the columns of a character gap columns after the end of columns.
+// A negative gap overlaps the character with the end of columns.
+func appendGlyph(columns []uint16, glyph []uint16, gap int) []uint16 {
+	if gap >= 0 {
+		columns = append(columns, make([]uint16, gap)...)
+		return append(columns, glyph...)
+	}
+
+	start := max(len(columns)+gap, 0)
+	for i, column := range glyph {
+		if start+i < len(columns) {
+			columns[start+i] |= column
+		} else {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// logSubstitutions warns about characters that are not shown as they are
+func logSubstitutions(font fonts.Font, substitutions []fonts.Substitution) {
+	if len(substitutions) == 0 {
+		return
+	}
+	var descriptions []string
+	for _, s := range substitutions {
+		descriptions = append(descriptions, s.String())
+	}
+	log.Warnf("Characters not in font %s: %s", font.Name(), strings.Join(descriptions, ", "))
+}
+
+// placeColumns copies font columns onto the display data starting at column x.
+// The rows of the font are centered vertically, so fonts higher than the display lose rows at the top and bottom.
+func (d *Display) placeColumns(displayData []uint32, columns []uint16, x int, fontHeight int) {
+	d.drawColumns(displayData, columns, x, (d.Height()-fontHeight)/2)
+}
+
+// drawColumns copies columns onto the display data with their top row at row y, dots outside the display are left out
+func (d *Display) drawColumns(displayData []uint32, columns []uint16, x int, y int) {
+	mask := uint32(1)<<d.Height() - 1
+	for i, column := range columns {
+		if x+i < 0 || x+i >= len(displayData) {
+			continue
+		}
+		displayData[x+i] |= shiftColumn(column, y) & mask
+	}
+}
+
+// shiftColumn moves a column down by offset rows, or up if offset is negative
+func shiftColumn(column uint16, offset int) uint32 {
+	if offset < 0 {
+		return uint32(column) >> -offset
+	}
+	return uint32(column) << offset
+}
+
+// sleep waits for the duration or until ctx is cancelled, in which case it returns the context's error
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -1,9 +1,15 @@
 package flipdot
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
 )
 
 // MockDisplayOutput for testing
@@ -11,14 +17,18 @@ type MockDisplayOutput struct {
 	ShowCalls []ShowCall
 	ShowError error
 	Closed    bool
+
+	mu sync.Mutex
 }
 
 type ShowCall struct {
-	DisplayData [28]uint16
+	DisplayData []uint32
 	Timestamp   time.Time
 }
 
-func (m *MockDisplayOutput) Show(displayData [28]uint16) error {
+func (m *MockDisplayOutput) Show(displayData []uint32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.ShowCalls = append(m.ShowCalls, ShowCall{
 		DisplayData: displayData,
 		Timestamp:   time.Now(),
@@ -31,30 +41,48 @@ func (m *MockDisplayOutput) Close() error {
 	return nil
 }
 
+// Calls returns a copy of the Show calls, for tests where Show is called from another goroutine
+func (m *MockDisplayOutput) Calls() []ShowCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ShowCall(nil), m.ShowCalls...)
+}
+
+// mockPort records everything written to a SerialOutput
+type mockPort struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (m *mockPort) Close() error {
+	m.closed = true
+	return nil
+}
+
 func TestPrepareSerialFrames(t *testing.T) {
-	s := &SerialOutput{}
+	s := &SerialOutput{layout: DefaultLayout()}
 
 	testCases := []struct {
 		name     string
-		input    [28]uint16
-		expected [2][28]byte
+		input    []uint32
+		expected [][]byte
 	}{
 		{
 			name:     "all zeros",
-			input:    [28]uint16{},
-			expected: [2][28]byte{},
+			input:    make([]uint32, 28),
+			expected: [][]byte{make([]byte, 28), make([]byte, 28)},
 		},
 		{
 			name: "all ones",
-			input: func() [28]uint16 {
-				var data [28]uint16
+			input: func() []uint32 {
+				data := make([]uint32, 28)
 				for i := range data {
 					data[i] = 0b11111111111111 // 14 bits set to 1
 				}
 				return data
 			}(),
-			expected: func() [2][28]byte {
-				var frames [2][28]byte
+			expected: func() [][]byte {
+				frames := [][]byte{make([]byte, 28), make([]byte, 28)}
 				for i := range frames[0] {
 					frames[0][i] = 0b1111111 // Lower 7 bits
 					frames[1][i] = 0b1111111 // Upper 7 bits
@@ -64,34 +92,34 @@ func TestPrepareSerialFrames(t *testing.T) {
 		},
 		{
 			name: "single bit top",
-			input: func() [28]uint16 {
-				var data [28]uint16
+			input: func() []uint32 {
+				data := make([]uint32, 28)
 				data[0] = 0b1 // bit 0
 				return data
 			}(),
-			expected: func() [2][28]byte {
-				var frames [2][28]byte
+			expected: func() [][]byte {
+				frames := [][]byte{make([]byte, 28), make([]byte, 28)}
 				frames[0][0] = 0b1
 				return frames
 			}(),
 		},
 		{
 			name: "single bit bottom",
-			input: func() [28]uint16 {
-				var data [28]uint16
+			input: func() []uint32 {
+				data := make([]uint32, 28)
 				data[0] = 0b10000000 // bit 7
 				return data
 			}(),
-			expected: func() [2][28]byte {
-				var frames [2][28]byte
+			expected: func() [][]byte {
+				frames := [][]byte{make([]byte, 28), make([]byte, 28)}
 				frames[1][0] = 0b1
 				return frames
 			}(),
 		},
 		{
 			name: "checkerboard",
-			input: func() [28]uint16 {
-				var data [28]uint16
+			input: func() []uint32 {
+				data := make([]uint32, 28)
 				for i := range data {
 					if i%2 == 0 {
 						data[i] = 0b10101010101010
@@ -101,8 +129,8 @@ func TestPrepareSerialFrames(t *testing.T) {
 				}
 				return data
 			}(),
-			expected: func() [2][28]byte {
-				var frames [2][28]byte
+			expected: func() [][]byte {
+				frames := [][]byte{make([]byte, 28), make([]byte, 28)}
 				for i := range frames[0] {
 					if i%2 == 0 {
 						frames[0][i] = 0b0101010
@@ -133,7 +161,7 @@ func TestPrepareSerialFrames(t *testing.T) {
 // Test Display creation
 func TestNewDisplay(t *testing.T) {
 	t.Run("terminal mode", func(t *testing.T) {
-		display, err := NewDisplay(true, "", 0)
+		display, err := NewDisplay(Config{Terminal: true})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -153,10 +181,11 @@ func TestNewDisplay(t *testing.T) {
 // Test Display with mock output
 func TestDisplayWithMock(t *testing.T) {
 	mock := &MockDisplayOutput{}
-	display := &Display{output: mock}
+	display := &Display{output: mock, layout: DefaultLayout()}
 
 	t.Run("Show method", func(t *testing.T) {
-		testData := [28]uint16{1, 2, 3, 4, 5}
+		testData := make([]uint32, 28)
+		copy(testData, []uint32{1, 2, 3, 4, 5})
 		err := display.Show(testData)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -186,7 +215,7 @@ func TestDisplayWithMock(t *testing.T) {
 // Test ShowTime method
 func TestDisplayShowTime(t *testing.T) {
 	mock := &MockDisplayOutput{}
-	display := &Display{output: mock}
+	display := &Display{output: mock, layout: DefaultLayout()}
 
 	err := display.ShowTime()
 	if err != nil {
@@ -221,7 +250,7 @@ func TestDisplayShowTime(t *testing.T) {
 // Test RunTestPattern method
 func TestDisplayRunTestPattern(t *testing.T) {
 	mock := &MockDisplayOutput{}
-	display := &Display{output: mock}
+	display := &Display{output: mock, layout: DefaultLayout()}
 
 	err := display.RunTestPattern()
 	if err != nil {
@@ -244,7 +273,7 @@ func TestDisplayRunTestPattern(t *testing.T) {
 // Test ShowText method
 func TestDisplayShowText(t *testing.T) {
 	mock := &MockDisplayOutput{}
-	display := &Display{output: mock}
+	display := &Display{output: mock, layout: DefaultLayout()}
 
 	t.Run("simple text no loop", func(t *testing.T) {
 		mock.ShowCalls = nil // Reset
@@ -276,7 +305,7 @@ func TestDisplayShowText(t *testing.T) {
 
 // Test prepareText method
 func TestDisplayPrepareText(t *testing.T) {
-	display := &Display{output: &MockDisplayOutput{}}
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
 
 	t.Run("valid text small", func(t *testing.T) {
 		result, err := display.prepareText("Hi", "small")
@@ -323,11 +352,11 @@ func TestDisplayPrepareText(t *testing.T) {
 
 // Test TerminalOutput
 func TestTerminalOutput(t *testing.T) {
-	terminal := &TerminalOutput{}
+	terminal := &TerminalOutput{height: 14}
 
 	t.Run("Show method", func(t *testing.T) {
 		// Create test pattern
-		testData := [28]uint16{}
+		testData := make([]uint32, 28)
 		testData[0] = 0b11111111111111 // All bits set in first column
 		testData[1] = 0b10101010101010 // Alternating pattern in second column
 
@@ -352,10 +381,10 @@ func TestSerialOutputMethods(t *testing.T) {
 	// Note: These tests focus on the logic, not actual serial communication
 
 	t.Run("prepareSerialFrames edge cases", func(t *testing.T) {
-		s := &SerialOutput{}
+		s := &SerialOutput{layout: DefaultLayout()}
 
 		// Test with maximum values
-		input := [28]uint16{}
+		input := make([]uint32, 28)
 		for i := range input {
 			input[i] = 0b11111111111111 // All 14 bits set
 		}
@@ -376,3 +405,529 @@ func TestSerialOutputMethods(t *testing.T) {
 		}
 	})
 }
+
+// Test the bytes written for each module type
+func TestSerialOutputModuleTypes(t *testing.T) {
+	layout, err := ParseLayout("1:7x7,2:7x7,3:7x14;4", Module7x28, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	port := &mockPort{}
+	s := &SerialOutput{port: port, layout: layout}
+
+	input := make([]uint32, 28)
+	input[0] = 0b1            // top left dot of panel 1
+	input[27] = 0b10000000000 // row 10, last column of panel 4
+
+	err = s.Show(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var expected []byte
+	expected = append(expected, 0x80, 0x87, 0x01, 0b1, 0, 0, 0, 0, 0, 0, 0x8F)
+	expected = append(expected, 0x80, 0x87, 0x02)
+	expected = append(expected, make([]byte, 7)...)
+	expected = append(expected, 0x8F)
+	expected = append(expected, 0x80, 0x92, 0x03)
+	expected = append(expected, make([]byte, 14)...)
+	expected = append(expected, 0x8F)
+	expected = append(expected, 0x80, 0x83, 0x04)
+	expected = append(expected, make([]byte, 27)...)
+	expected = append(expected, 0b1000, 0x8F)
+
+	if !bytes.Equal(port.Bytes(), expected) {
+		t.Errorf("unexpected bytes written:\nexpected: % x\nactual:   % x", expected, port.Bytes())
+	}
+
+	t.Run("unknown module size", func(t *testing.T) {
+		s := &SerialOutput{port: &mockPort{}, layout: GridLayout([][]byte{{1}}, 20, 7, 0)}
+		if err := s.Show(make([]uint32, 20)); err == nil {
+			t.Fatal("expected error for a panel that is not a known module type")
+		}
+	})
+
+	t.Run("module type by name", func(t *testing.T) {
+		m, err := ModuleTypeByName("7x14")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m != Module7x14 {
+			t.Errorf("expected %v, got %v", Module7x14, m)
+		}
+		if _, err := ModuleTypeByName("7x56"); err == nil {
+			t.Fatal("expected error for unknown module type")
+		}
+	})
+}
+
+// Test buffered mode sends every panel without refreshing and then a single flush
+func TestSerialOutputBuffered(t *testing.T) {
+	testCases := []struct {
+		name     string
+		layout   string
+		input    []uint32
+		expected [][]byte
+	}{
+		{
+			name:   "default layout",
+			layout: "1;2",
+			input: func() []uint32 {
+				data := make([]uint32, 28)
+				data[0] = 0b10000001 // top row of both panels
+				return data
+			}(),
+			expected: [][]byte{
+				append(append([]byte{0x80, 0x84, 0x01, 0b1}, make([]byte, 27)...), 0x8F),
+				append(append([]byte{0x80, 0x84, 0x02, 0b1}, make([]byte, 27)...), 0x8F),
+				{0x80, 0x82, 0x8F},
+			},
+		},
+		{
+			name:   "7x14 modules",
+			layout: "1:7x14,2:7x14",
+			input:  make([]uint32, 28),
+			expected: [][]byte{
+				append(append([]byte{0x80, 0x93, 0x01}, make([]byte, 14)...), 0x8F),
+				append(append([]byte{0x80, 0x93, 0x02}, make([]byte, 14)...), 0x8F),
+				{0x80, 0x82, 0x8F},
+			},
+		},
+		{
+			name:   "7x7 modules are refreshed straight away",
+			layout: "1:7x7,2:7x14",
+			input:  make([]uint32, 21),
+			expected: [][]byte{
+				append(append([]byte{0x80, 0x87, 0x01}, make([]byte, 7)...), 0x8F),
+				append(append([]byte{0x80, 0x93, 0x02}, make([]byte, 14)...), 0x8F),
+				{0x80, 0x82, 0x8F},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layout, err := ParseLayout(tc.layout, Module7x28, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			port := &mockPort{}
+			s := &SerialOutput{port: port, layout: layout, buffered: true}
+
+			err = s.Show(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := bytes.Join(tc.expected, nil)
+			if !bytes.Equal(port.Bytes(), expected) {
+				t.Errorf("unexpected bytes written:\nexpected: % x\nactual:   % x", expected, port.Bytes())
+			}
+		})
+	}
+}
+
+// Test that only panels that changed are sent again
+func TestSerialOutputDifferential(t *testing.T) {
+	port := &mockPort{}
+	s := &SerialOutput{port: port, layout: DefaultLayout(), buffered: true}
+
+	topDot := make([]uint32, 28)
+	topDot[0] = 0b1
+	bottomDot := make([]uint32, 28)
+	bottomDot[0] = 0b1 | 1<<7
+
+	steps := []struct {
+		name     string
+		input    []uint32
+		expected [][]byte
+	}{
+		{
+			name:  "first frame sends all panels",
+			input: topDot,
+			expected: [][]byte{
+				serialFrame(0x84, 0x01, append([]byte{0b1}, make([]byte, 27)...)),
+				serialFrame(0x84, 0x02, make([]byte, 28)),
+				flushFrame,
+			},
+		},
+		{
+			name:     "same frame sends nothing",
+			input:    topDot,
+			expected: nil,
+		},
+		{
+			name:  "only the changed panel is sent",
+			input: bottomDot,
+			expected: [][]byte{
+				serialFrame(0x84, 0x02, append([]byte{0b1}, make([]byte, 27)...)),
+				flushFrame,
+			},
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			port.Reset()
+			err := s.Show(step.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := bytes.Join(step.expected, nil)
+			if !bytes.Equal(port.Bytes(), expected) {
+				t.Errorf("unexpected bytes written:\nexpected: % x\nactual:   % x", expected, port.Bytes())
+			}
+		})
+	}
+
+	t.Run("full refresh interval sends all panels", func(t *testing.T) {
+		s.fullRefreshInterval = 10 * time.Minute
+		s.lastFullRefresh = time.Now().Add(-11 * time.Minute)
+		port.Reset()
+
+		err := s.Show(bottomDot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port.Len() != 2*len(serialFrame(0x84, 0x01, make([]byte, 28)))+len(flushFrame) {
+			t.Errorf("expected both panels and a flush to be sent, got % x", port.Bytes())
+		}
+
+		port.Reset()
+		err = s.Show(bottomDot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port.Len() != 0 {
+			t.Errorf("expected nothing to be sent until the next full refresh, got % x", port.Bytes())
+		}
+	})
+}
+
+// Test that long running operations stop when the context is cancelled
+func TestDisplayContextCancellation(t *testing.T) {
+	testCases := []struct {
+		name string
+		run  func(ctx context.Context, d *Display) error
+	}{
+		{
+			name: "looping text",
+			run: func(ctx context.Context, d *Display) error {
+				return d.ShowTextContext(ctx, "Hi", 1*time.Millisecond, true, "small")
+			},
+		},
+		{
+			name: "clock",
+			run: func(ctx context.Context, d *Display) error {
+				return d.RunClock(ctx)
+			},
+		},
+		{
+			name: "test pattern",
+			run: func(ctx context.Context, d *Display) error {
+				return d.RunTestPatternContext(ctx)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			done := make(chan error)
+			go func() {
+				done <- tc.run(ctx, display)
+			}()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected context error, got %v", err)
+				}
+			case <-time.After(1 * time.Second):
+				t.Fatal("did not stop after the context was cancelled")
+			}
+		})
+	}
+}
+
+// Test Clear method
+func TestDisplayClear(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout()}
+
+	err := display.Clear()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(mock.ShowCalls[0].DisplayData, make([]uint32, 28)) {
+		t.Errorf("expected a blank display, got %v", mock.ShowCalls[0].DisplayData)
+	}
+}
+
+// Test that clock formats are checked when the display is created
+func TestClockFormat(t *testing.T) {
+	testCases := []struct {
+		format       string
+		font         string
+		date         string
+		secondsBar   bool
+		pmDot        bool
+		expectError  bool
+		expectedFont string
+	}{
+		{format: "", expectedFont: "small"},
+		{format: "15:04", expectedFont: "small"},
+		{format: "3:04", expectedFont: "small"},
+		// 6 digits and 2 colons only fit in 28 columns in the condensed font
+		{format: "15:04:05", expectedFont: "condensed"},
+		{format: "15:04:05", font: "small", expectError: true},
+		// 4 digits and a '/' are 29 columns wide in the small font, one more than the display
+		{format: "01/02", expectedFont: "condensed"},
+		// too wide for the small font and the condensed font has no lowercase letters
+		{format: "Mon 15:04", expectError: true},
+		{format: "15:04", font: "huge", expectError: true},
+		{format: "%-I:%M", pmDot: true, expectedFont: "small"},
+		{format: "%H:%Q", expectError: true},
+		// the small font leaves the bottom rows free for the seconds bar, the large font does not
+		{format: "15:04", secondsBar: true, expectedFont: "small"},
+		{format: "15:04", font: "large", secondsBar: true, expectError: true},
+		// the small font and the date fill the 14 rows, with the seconds bar only the condensed font fits
+		{format: "15:04", date: "Mon 2", expectedFont: "small"},
+		{format: "15:04", date: "%a %-d", secondsBar: true, expectedFont: "condensed"},
+		{format: "15:04", date: "Monday 2 January", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format+tc.font+tc.date, func(t *testing.T) {
+			display, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockFormat: tc.format, ClockFont: tc.font,
+				ClockDate: tc.date, ClockSecondsBar: tc.secondsBar, ClockPMDot: tc.pmDot})
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if display.clockFont != tc.expectedFont {
+				t.Errorf("expected the %s font, got %s", tc.expectedFont, display.clockFont)
+			}
+			display.Close()
+		})
+	}
+}
+
+// Test the date row, seconds bar and PM dot of the clock
+func TestClockFrame(t *testing.T) {
+	display := &Display{layout: DefaultLayout(), clockFormat: "3:04", clockFont: "condensed", clockDate: "Mon 2",
+		clockSecondsBar: true, clockPMDot: true}
+	bottom := uint32(1) << (display.Height() - 1)
+
+	displayData, err := display.clockFrame(time.Date(2024, time.March, 4, 15, 7, 29, 0, time.UTC), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if displayData[0]&1 == 0 {
+		t.Errorf("expected the PM dot in the afternoon, got %b", displayData[0])
+	}
+	// half a minute fills half of the bar
+	for i, column := range displayData {
+		if on := column&bottom != 0; on != (i < 14) {
+			t.Errorf("expected column %d of the seconds bar to be %v, got %b", i, i < 14, column)
+		}
+	}
+	// the time is in the 7 rows at the top, the date in the 5 rows under it after a blank row
+	var rows uint32
+	for _, column := range displayData[1:] {
+		rows |= column &^ bottom
+	}
+	if rows != 0b1_1111_0111_1111 {
+		t.Errorf("expected the time and date on rows 0 to 6 and 8 to 12, got %b", rows)
+	}
+
+	displayData, err = display.clockFrame(time.Date(2024, time.March, 4, 9, 7, 0, 0, time.UTC), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if displayData[0]&1 != 0 {
+		t.Errorf("expected no PM dot in the morning, got %b", displayData[0])
+	}
+	if displayData[0]&bottom != 0 {
+		t.Errorf("expected the seconds bar to be empty at the start of the minute, got %b", displayData[0])
+	}
+}
+
+// Test that every time of the day in the tall font fills the height of the display and fits its width
+func TestClockTallFont(t *testing.T) {
+	display := &Display{layout: DefaultLayout(), clockFont: "tall"}
+	if err := display.checkClockFormat(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	for minute := range 24 * 60 {
+		now := start.Add(time.Duration(minute) * time.Minute)
+		displayData, err := display.clockFrame(now, "")
+		if err != nil {
+			t.Fatalf("unexpected error at %s: %v", now.Format("15:04"), err)
+		}
+		var rows uint32
+		for _, column := range displayData {
+			rows |= column
+		}
+		if rows != 1<<display.Height()-1 {
+			t.Fatalf("expected %s to use all %d rows, got %b", now.Format("15:04"), display.Height(), rows)
+		}
+	}
+
+	// 4 digits 5 columns wide, the colon and the gaps between them leave one column free on each side
+	columns, err := display.prepareTime("20:28")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(columns) != display.Width()-2 {
+		t.Errorf("expected the time to be %d columns wide, got %d", display.Width()-2, len(columns))
+	}
+
+	// seconds do not fit
+	display.clockFormat = "15:04:05"
+	if err := display.checkClockFormat(); err == nil {
+		t.Error("expected error for seconds in the tall font")
+	}
+}
+
+func TestClockInterval(t *testing.T) {
+	testCases := []struct {
+		display  *Display
+		expected time.Duration
+	}{
+		{display: &Display{}, expected: time.Minute},
+		{display: &Display{clockFormat: "15:04:05"}, expected: time.Second},
+		{display: &Display{clockSecondsBar: true}, expected: time.Second},
+		{display: &Display{clockDate: "Mon 2"}, expected: time.Minute},
+	}
+	for _, tc := range testCases {
+		if interval := tc.display.clockInterval(); interval != tc.expected {
+			t.Errorf("expected %v for %+v, got %v", tc.expected, tc.display, interval)
+		}
+	}
+}
+
+// Test that the display is blank outside the clock schedule
+func TestRunClockSchedule(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	// 2024-03-04 is a Monday, the clock is only shown on Tuesdays
+	display := &Display{output: mock, layout: DefaultLayout(), clockSchedule: Schedule{
+		{Days: []time.Weekday{time.Tuesday}, Start: 0, End: 24 * time.Hour},
+	}}
+	runFakeClock(t, display, &fakeClock{now: time.Date(2024, time.March, 4, 23, 59, 30, 0, time.UTC), max: 1})
+
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 Show call, got %d", len(calls))
+	}
+	for i, column := range calls[0].DisplayData {
+		if column != 0 {
+			t.Errorf("expected column %d to be blank, got %b", i, column)
+		}
+	}
+}
+
+func init() {
+	// registered once, the registry is shared by every run of the tests
+	fonts.Register(fonts.NewBitmapFont("test-bar", 2, 1, map[rune][]uint16{'-': {0b11, 0b11}}))
+	fonts.Register(fonts.NewBitmapFont("test-spacing", 2, 1, map[rune][]uint16{
+		'|': {0b11},
+		'T': {0b01, 0b11, 0b01},
+		'.': {0b10},
+	}))
+}
+
+// Test that text can be shown in any registered font, centered vertically
+func TestDisplayRegisteredFont(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout()}
+	if err := display.ShowTextContext(context.Background(), "-", 0, false, "test-bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the text scrolls in from the right, after 28 steps the character is in the first columns
+	// on rows 6 and 7 of the 14
+	frame := mock.Calls()[28].DisplayData
+	if frame[0] != 0b11<<6 || frame[1] != 0b11<<6 {
+		t.Errorf("expected the character to be centered, got %b", frame[:2])
+	}
+}
+
+func TestDisplayTextFallback(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+	if _, err := display.CheckText("Café", "large"); err == nil {
+		t.Errorf("expected an error without a fallback")
+	}
+
+	display.textFallback = fonts.Fallback{StripAccents: true, Missing: fonts.MissingSkip}
+	substitutions, err := display.CheckText("Café 🚀!", "large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(substitutions) != 2 || substitutions[0].String() != "'é' as 'e' from large" || substitutions[1].String() != "'🚀' skipped" {
+		t.Errorf("unexpected substitutions %v", substitutions)
+	}
+
+	// a skipped character takes no space
+	withSkipped, _, err := display.renderText("a🚀b", mustLookup(t, "large"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	without, _, _ := display.renderText("ab", mustLookup(t, "large"))
+	if !reflect.DeepEqual(withSkipped, without) {
+		t.Errorf("expected a skipped character to take no space, got %b", withSkipped)
+	}
+}
+
+func mustLookup(t *testing.T, name string) fonts.Font {
+	t.Helper()
+	font, err := fonts.Lookup(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return font
+}
+
+// Test that text is drawn with the spacing of its font
+func TestDisplayTextSpacing(t *testing.T) {
+	t.Cleanup(func() { fonts.SetSpacing("test-spacing", fonts.DefaultSpacing) })
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+
+	tests := []struct {
+		name     string
+		spacing  fonts.Spacing
+		text     string
+		expected []uint16
+	}{
+		{name: "default", spacing: fonts.DefaultSpacing, text: "||", expected: []uint16{0b11, 0, 0b11}},
+		{name: "wide", spacing: fonts.Spacing{Letter: 2}, text: "||", expected: []uint16{0b11, 0, 0, 0b11}},
+		{name: "condensed", spacing: fonts.Spacing{Letter: 0}, text: "||", expected: []uint16{0b11, 0b11}},
+		{name: "kerning", spacing: fonts.Spacing{Letter: 1, Kerning: map[string]int{"|T": 1}}, text: "|T|", expected: []uint16{0b11, 0, 0, 0b01, 0b11, 0b01, 0, 0b11}},
+		// the dot moves under the right of the T
+		{name: "overlap", spacing: fonts.Spacing{Letter: 1, Kerning: map[string]int{"T.": -2}}, text: "T.", expected: []uint16{0b01, 0b11, 0b11}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := fonts.SetSpacing("test-spacing", tc.spacing); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			columns, _, err := display.renderText(tc.text, mustLookup(t, "test-spacing"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(columns, tc.expected) {
+				t.Errorf("expected %b, got %b", tc.expected, columns)
+			}
+		})
+	}
+}
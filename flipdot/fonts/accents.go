@@ -0,0 +1,45 @@
+package fonts
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// asciiEquivalents has letters that have no Unicode decomposition written with ASCII letters, like 'ß' and 'ø',
+// and typographic punctuation replaced by its ASCII equivalent
+var asciiEquivalents = map[rune]string{
+	'Æ': "AE", 'æ': "ae", 'Ð': "D", 'ð': "d", 'Ø': "O", 'ø': "o", 'Þ': "Th", 'þ': "th",
+	'ß': "ss", 'Đ': "D", 'đ': "d", 'Ħ': "H", 'ħ': "h", 'ı': "i", 'Ĳ': "IJ", 'ĳ': "ij",
+	'ĸ': "k", 'Ŀ': "L", 'ŀ': "l", 'Ł': "L", 'ł': "l", 'ŉ': "'n", 'Ŋ': "N", 'ŋ': "n",
+	'Œ': "OE", 'œ': "oe", 'Ŧ': "T", 'ŧ': "t", 'ſ': "s",
+
+	// Punctuation and symbols
+	'\u00a0': " ", '¡': "!", '¿': "?", '«': "\"", '»': "\"", '·': ".",
+	'×': "x", '÷': "/", '\u2010': "-", '\u2011': "-", '\u2012': "-", '\u2013': "-",
+	'\u2014': "-", '\u2015': "-", '\u2018': "'", '\u2019': "'", '\u201a': "'", '\u201b': "'",
+	'\u201c': "\"", '\u201d': "\"", '\u201e': "\"", '\u201f': "\"", '•': "*", '…': "...",
+	'‹': "<", '›': ">",
+}
+
+// asciiEquivalent returns a character without its accents, its canonical Unicode decomposition without the
+// combining marks, e.g. 'ș' as 's' and 'ệ' as 'e', or its entry in asciiEquivalents.
+// ok is false if the character has neither.
+func asciiEquivalent(char rune) (string, bool) {
+	var result strings.Builder
+	for _, r := range norm.NFD.String(string(char)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if equivalent, ok := asciiEquivalents[r]; ok {
+			result.WriteString(equivalent)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	if result.Len() == 0 || result.String() == string(char) {
+		return "", false
+	}
+	return result.String(), true
+}
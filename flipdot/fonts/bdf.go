@@ -0,0 +1,179 @@
+package fonts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// bdfMaxWidth is the widest BDF character that is read, as bitmap rows are read into 64 bits
+const bdfMaxWidth = 64
+
+// ParseBDF reads a font in the Glyph Bitmap Distribution Format (BDF)
+// Characters without a Unicode encoding are skipped.
+func ParseBDF(r io.Reader) (*BitmapFont, error) {
+	scanner := bufio.NewScanner(r)
+
+	var boxHeight, boxYOffset int
+	hasBox := false
+	characters := map[rune][]uint16{}
+
+	var glyph *bdfGlyph
+	inBitmap := false
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inBitmap {
+			if fields[0] == "ENDCHAR" {
+				inBitmap = false
+				if glyph.encoding >= 0 {
+					columns, err := glyph.columns(boxHeight, boxYOffset)
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %v", line, err)
+					}
+					characters[rune(glyph.encoding)] = columns
+				}
+				glyph = nil
+				continue
+			}
+			row, err := strconv.ParseUint(fields[0], 16, 64)
+			if err != nil || len(fields[0]) > 16 {
+				return nil, fmt.Errorf("line %d: invalid bitmap row '%s'", line, fields[0])
+			}
+			// rows are padded to whole bytes, the first pixel is the highest bit
+			glyph.rows = append(glyph.rows, row<<(64-4*len(fields[0])))
+			continue
+		}
+
+		numbers, err := bdfNumbers(fields[1:])
+		switch fields[0] {
+		case "FONTBOUNDINGBOX":
+			if err != nil || len(numbers) != 4 {
+				return nil, fmt.Errorf("line %d: invalid FONTBOUNDINGBOX", line)
+			}
+			boxHeight, boxYOffset = numbers[1], numbers[3]
+			hasBox = true
+			if boxHeight < 1 || boxHeight > maxHeight {
+				return nil, fmt.Errorf("font is %d pixels high, at most %d are supported", boxHeight, maxHeight)
+			}
+		case "STARTCHAR":
+			if !hasBox {
+				return nil, fmt.Errorf("line %d: STARTCHAR before FONTBOUNDINGBOX", line)
+			}
+			glyph = &bdfGlyph{name: strings.Join(fields[1:], " "), encoding: -1}
+		case "ENCODING":
+			if glyph == nil || err != nil || len(numbers) < 1 {
+				return nil, fmt.Errorf("line %d: invalid ENCODING", line)
+			}
+			glyph.encoding = numbers[0]
+		case "DWIDTH":
+			if glyph == nil || err != nil || len(numbers) < 1 {
+				return nil, fmt.Errorf("line %d: invalid DWIDTH", line)
+			}
+			glyph.advance = numbers[0]
+			if glyph.advance < 0 || glyph.advance > bdfMaxWidth {
+				return nil, fmt.Errorf("line %d: character '%s' advances %d pixels, it must be between 0 and %d", line, glyph.name, glyph.advance, bdfMaxWidth)
+			}
+		case "BBX":
+			if glyph == nil || err != nil || len(numbers) != 4 {
+				return nil, fmt.Errorf("line %d: invalid BBX", line)
+			}
+			glyph.width, glyph.height, glyph.xOffset, glyph.yOffset = numbers[0], numbers[1], numbers[2], numbers[3]
+			if glyph.width < 1 || max(glyph.xOffset, 0)+glyph.width > bdfMaxWidth {
+				return nil, fmt.Errorf("line %d: character '%s' is %d pixels wide at %d, at most %d are supported", line, glyph.name, glyph.width, glyph.xOffset, bdfMaxWidth)
+			}
+			if glyph.height < 0 {
+				return nil, fmt.Errorf("line %d: character '%s' is %d pixels high", line, glyph.name, glyph.height)
+			}
+		case "BITMAP":
+			if glyph == nil {
+				return nil, fmt.Errorf("line %d: BITMAP outside of a character", line)
+			}
+			inBitmap = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read font: %v", err)
+	}
+	if !hasBox {
+		return nil, fmt.Errorf("not a BDF font, FONTBOUNDINGBOX not found")
+	}
+	if len(characters) == 0 {
+		return nil, fmt.Errorf("font has no characters")
+	}
+
+	// the baseline is the row above the origin, which is boxYOffset rows above the bottom of the font
+	return NewBitmapFont("", boxHeight, boxHeight+boxYOffset-1, characters), nil
+}
+
+// bdfGlyph is a character of a BDF font as it is read
+type bdfGlyph struct {
+	name     string
+	encoding int
+	advance  int
+	width    int
+	height   int
+	xOffset  int
+	yOffset  int
+	// rows of the bitmap, the first pixel is the highest bit
+	rows []uint64
+}
+
+// columns converts the glyph to one uint16 per column, the lowest bit is the top row of the font's bounding box
+func (g *bdfGlyph) columns(boxHeight int, boxYOffset int) ([]uint16, error) {
+	if len(g.rows) != g.height {
+		return nil, fmt.Errorf("character %d has %d bitmap rows, BBX says %d", g.encoding, len(g.rows), g.height)
+	}
+
+	// the glyph's offsets are from the origin on the baseline, the font box starts boxYOffset below it
+	top := (boxHeight + boxYOffset) - (g.yOffset + g.height)
+	left := max(g.xOffset, 0)
+
+	columns := make([]uint16, max(g.advance, left+g.width))
+	for y, row := range g.rows {
+		fontRow := top + y
+		if fontRow < 0 || fontRow >= boxHeight {
+			continue
+		}
+		for x := range g.width {
+			if row&(1<<(63-x)) != 0 {
+				columns[left+x] |= 1 << fontRow
+			}
+		}
+	}
+
+	return trimColumns(columns, g.advance), nil
+}
+
+// trimColumns removes the blank columns on the right, which fonts use as the gap to the next character.
+// The renderer adds its own gap. A character without any dots, like space, keeps its width without the gap.
+func trimColumns(columns []uint16, advance int) []uint16 {
+	end := len(columns)
+	for end > 0 && columns[end-1] == 0 {
+		end--
+	}
+	if end == 0 {
+		return make([]uint16, max(advance-1, 1))
+	}
+	return columns[:end]
+}
+
+func bdfNumbers(fields []string) ([]int, error) {
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
@@ -0,0 +1,159 @@
+package fonts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testBDF is a 4x6 font with 1 pixel below the baseline
+const testBDF = `STARTFONT 2.1
+FONT -test-tiny-medium-r-normal--6-60-75-75-c-40-iso10646-1
+SIZE 6 75 75
+FONTBOUNDINGBOX 4 6 0 -1
+STARTPROPERTIES 2
+FONT_ASCENT 5
+FONT_DESCENT 1
+ENDPROPERTIES
+CHARS 4
+STARTCHAR space
+ENCODING 32
+SWIDTH 640 0
+DWIDTH 4 0
+BBX 4 6 0 -1
+BITMAP
+00
+00
+00
+00
+00
+00
+ENDCHAR
+STARTCHAR A
+ENCODING 65
+SWIDTH 640 0
+DWIDTH 4 0
+BBX 3 5 0 0
+BITMAP
+40
+A0
+E0
+A0
+A0
+ENDCHAR
+STARTCHAR j
+ENCODING 106
+SWIDTH 640 0
+DWIDTH 4 0
+BBX 2 5 1 -1
+BITMAP
+40
+00
+40
+40
+80
+ENDCHAR
+STARTCHAR unencoded
+ENCODING -1
+DWIDTH 4 0
+BBX 1 1 0 0
+BITMAP
+80
+ENDCHAR
+ENDFONT
+`
+
+func TestParseBDF(t *testing.T) {
+	font, err := ParseBDF(strings.NewReader(testBDF))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if font.height != 6 {
+		t.Errorf("expected height 6, got %d", font.height)
+	}
+
+	expected := map[rune][]uint16{
+		// the gap after the character is dropped, space keeps its width without the gap
+		' ': {0, 0, 0},
+		// the capital A fills the 5 rows above the baseline
+		'A': {0b011110, 0b000101, 0b011110},
+		// starts at the 2nd column and goes below the baseline on row 5
+		'j': {0b000000, 0b100000, 0b011010},
+	}
+	if !reflect.DeepEqual(font.characters, expected) {
+		t.Errorf("unexpected characters:\nexpected: %b\nactual:   %b", expected, font.characters)
+	}
+}
+
+func TestParseBDFErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		font string
+	}{
+		{name: "not a BDF font", font: "hello"},
+		{name: "too high", font: "FONTBOUNDINGBOX 8 20 0 -4\n"},
+		{name: "missing rows", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 3 5 0 0\nBITMAP\n40\nENDCHAR\n"},
+		{name: "invalid row", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 3 1 0 0\nBITMAP\nzz\nENDCHAR\n"},
+		{name: "too wide", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 72 1 0 0\nBITMAP\n00\nENDCHAR\n"},
+		{name: "no width", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX -3 1 0 0\nBITMAP\n00\nENDCHAR\n"},
+		{name: "offset too wide", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 8 1 60 0\nBITMAP\n00\nENDCHAR\n"},
+		{name: "negative height", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 3 -1 0 0\nBITMAP\nENDCHAR\n"},
+		{name: "negative advance", font: "FONTBOUNDINGBOX 4 6 0 -1\nSTARTCHAR A\nENCODING 65\nDWIDTH -4 0\nBBX 3 1 0 0\nBITMAP\n40\nENDCHAR\n"},
+		{name: "no characters", font: "FONTBOUNDINGBOX 4 6 0 -1\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseBDF(strings.NewReader(tc.font)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tiny.bdf")
+	if err := os.WriteFile(path, []byte(testBDF), 0644); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+
+	font, err := LoadFont(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregister("tiny") })
+	if font.name != "tiny" {
+		t.Errorf("expected font to be named after the file, got %s", font.name)
+	}
+
+	// the 6 row font is centered in the 14 rows the built in fonts use
+	columns, err := GetCharacter('A', "tiny")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(columns, []uint16{0b011110 << 4, 0b000101 << 4, 0b011110 << 4}) {
+		t.Errorf("unexpected columns: %b", columns)
+	}
+
+	if _, err := GetCharacter('B', "tiny"); err == nil {
+		t.Error("expected error for a character that is not in the font")
+	}
+	if names := Names(); !slices.Contains(names, "tiny") {
+		t.Errorf("expected names to include the loaded font, got %v", names)
+	}
+	if font.Baseline() != 4 {
+		t.Errorf("expected baseline on row 4, got %d", font.Baseline())
+	}
+
+	if _, err := LoadFont(path); err == nil {
+		t.Error("expected error for loading a font with the same name twice")
+	}
+
+	if _, err := LoadFont(filepath.Join(t.TempDir(), "font.ttf")); err == nil {
+		t.Error("expected error for a font file that is not supported")
+	}
+}
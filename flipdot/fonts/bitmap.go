@@ -0,0 +1,32 @@
+package fonts
+
+// BitmapFont is a Font with the columns of every character in a map
+type BitmapFont struct {
+	name       string
+	height     int
+	baseline   int
+	characters map[rune][]uint16
+}
+
+// NewBitmapFont creates a font from the columns of its characters, the lowest bit of a column is the top row.
+// baseline is the row the characters sit on.
+func NewBitmapFont(name string, height int, baseline int, characters map[rune][]uint16) *BitmapFont {
+	return &BitmapFont{name: name, height: height, baseline: baseline, characters: characters}
+}
+
+func (f *BitmapFont) Name() string {
+	return f.name
+}
+
+func (f *BitmapFont) Glyph(char rune) ([]uint16, bool) {
+	columns, ok := f.characters[char]
+	return columns, ok
+}
+
+func (f *BitmapFont) Height() int {
+	return f.height
+}
+
+func (f *BitmapFont) Baseline() int {
+	return f.baseline
+}
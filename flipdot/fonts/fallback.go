@@ -0,0 +1,139 @@
+package fonts
+
+import "fmt"
+
+// MissingStrategy is what is shown for a character that no font has
+type MissingStrategy int
+
+const (
+	// MissingError makes text with the character an error
+	MissingError MissingStrategy = iota
+	// MissingReplace shows Fallback.Replacement instead of the character
+	MissingReplace
+	// MissingSkip leaves the character out
+	MissingSkip
+)
+
+var missingStrategyNames = map[string]MissingStrategy{
+	"error":   MissingError,
+	"replace": MissingReplace,
+	"skip":    MissingSkip,
+}
+
+// ParseMissingStrategy returns the strategy with a name of "error", "replace" or "skip"
+func ParseMissingStrategy(name string) (MissingStrategy, error) {
+	strategy, ok := missingStrategyNames[name]
+	if !ok {
+		return 0, fmt.Errorf("missing character strategy '%s' not supported, must be one of 'error', 'replace' or 'skip'", name)
+	}
+	return strategy, nil
+}
+
+// Fallback decides what is shown for characters a font does not have.
+// The zero value makes them an error.
+type Fallback struct {
+	// StripAccents shows letters with accents without them, e.g. 'é' as 'e', and typographic
+	// punctuation like '“' as its ASCII equivalent
+	StripAccents bool
+	// Fonts are the names of fonts to take characters from, in order, when the font does not have them
+	Fonts []string
+	// Missing is what happens to characters that are still not found
+	Missing MissingStrategy
+	// Replacement is shown for missing characters with MissingReplace, '?' if zero
+	Replacement rune
+}
+
+// Substitution is a character that was not in the font and what was shown instead
+type Substitution struct {
+	Char rune
+	// With is what was shown instead, empty if the character was left out
+	With string
+	// Font is the font With was taken from
+	Font string
+}
+
+func (s Substitution) String() string {
+	if s.With == "" {
+		return fmt.Sprintf("'%c' skipped", s.Char)
+	}
+	return fmt.Sprintf("'%c' as '%s' from %s", s.Char, s.With, s.Font)
+}
+
+// Glyph returns the columns of a character in font, or what the fallback shows instead.
+// The substitution is nil if the font has the character.
+// Characters from other fonts are moved to sit on the font's baseline.
+func (f Fallback) Glyph(font Font, char rune) ([]uint16, *Substitution, error) {
+	if columns, ok := font.Glyph(char); ok {
+		return columns, nil, nil
+	}
+
+	candidates := []string{string(char)}
+	if equivalent, ok := asciiEquivalent(char); ok && f.StripAccents {
+		candidates = append(candidates, equivalent)
+	}
+
+	fonts := []Font{font}
+	for _, name := range f.Fonts {
+		fallbackFont, err := Lookup(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid fallback font: %v", err)
+		}
+		fonts = append(fonts, fallbackFont)
+	}
+
+	// the character from another font is better than the character without its accents
+	for _, candidate := range candidates {
+		for _, from := range fonts {
+			if columns, ok := glyphs(from, candidate, font); ok {
+				return columns, &Substitution{Char: char, With: candidate, Font: from.Name()}, nil
+			}
+		}
+	}
+
+	switch f.Missing {
+	case MissingReplace:
+		replacement := f.Replacement
+		if replacement == 0 {
+			replacement = '?'
+		}
+		if columns, ok := font.Glyph(replacement); ok {
+			return columns, &Substitution{Char: char, With: string(replacement), Font: font.Name()}, nil
+		}
+		return nil, nil, fmt.Errorf("character '%c' in font '%s' not found, nor its replacement '%c'", char, font.Name(), replacement)
+	case MissingSkip:
+		return nil, &Substitution{Char: char}, nil
+	default:
+		return nil, nil, fmt.Errorf("character '%c' in font '%s' not found", char, font.Name())
+	}
+}
+
+// glyphs returns the columns of all characters of text in from, with a gap between them,
+// moved to the baseline of font
+func glyphs(from Font, text string, font Font) ([]uint16, bool) {
+	var result []uint16
+	for i, char := range []rune(text) {
+		columns, ok := from.Glyph(char)
+		if !ok {
+			return nil, false
+		}
+		if i > 0 {
+			result = append(result, 0)
+		}
+		result = append(result, columns...)
+	}
+
+	shift := font.Baseline() - from.Baseline()
+	if shift == 0 {
+		return result, true
+	}
+	mask := uint16(1)<<font.Height() - 1
+	moved := make([]uint16, len(result))
+	for i, column := range result {
+		if shift > 0 {
+			moved[i] = column << shift & mask
+		} else {
+			moved[i] = column >> -shift & mask
+		}
+	}
+	return moved, true
+}
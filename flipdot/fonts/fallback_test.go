@@ -0,0 +1,77 @@
+package fonts
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	font := NewBitmapFont("test-ascii", 5, 4, map[rune][]uint16{
+		'e': {0b11100, 0b10100},
+		's': {0b10000},
+		'?': {0b00001},
+	})
+	// a font with its baseline one row higher
+	symbols := NewBitmapFont("test-symbols", 5, 3, map[rune][]uint16{'€': {0b01110}})
+	if err := Register(symbols); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregister("test-symbols") })
+
+	tests := []struct {
+		name         string
+		fallback     Fallback
+		char         rune
+		expected     []uint16
+		substitution string
+		err          bool
+	}{
+		{name: "in font", char: 'e', expected: []uint16{0b11100, 0b10100}},
+		{name: "accent", fallback: Fallback{StripAccents: true}, char: 'é', expected: []uint16{0b11100, 0b10100}, substitution: "'é' as 'e' from test-ascii"},
+		{name: "several characters", fallback: Fallback{StripAccents: true}, char: 'ß', expected: []uint16{0b10000, 0, 0b10000}, substitution: "'ß' as 'ss' from test-ascii"},
+		{name: "comma below", fallback: Fallback{StripAccents: true}, char: 'ș', expected: []uint16{0b10000}, substitution: "'ș' as 's' from test-ascii"},
+		{name: "two accents", fallback: Fallback{StripAccents: true}, char: 'ệ', expected: []uint16{0b11100, 0b10100}, substitution: "'ệ' as 'e' from test-ascii"},
+		{name: "no decomposition", fallback: Fallback{StripAccents: true, Missing: MissingSkip}, char: 'ø', substitution: "'ø' skipped"},
+		{name: "accent not stripped", char: 'é', err: true},
+		{name: "fallback font", fallback: Fallback{Fonts: []string{"test-symbols"}}, char: '€', expected: []uint16{0b11100}, substitution: "'€' as '€' from test-symbols"},
+		{name: "replace", fallback: Fallback{Missing: MissingReplace}, char: '€', expected: []uint16{0b00001}, substitution: "'€' as '?' from test-ascii"},
+		{name: "replace with", fallback: Fallback{Missing: MissingReplace, Replacement: 's'}, char: '€', expected: []uint16{0b10000}, substitution: "'€' as 's' from test-ascii"},
+		{name: "replacement not in font", fallback: Fallback{Missing: MissingReplace, Replacement: '#'}, char: '€', err: true},
+		{name: "skip", fallback: Fallback{Missing: MissingSkip}, char: '€', substitution: "'€' skipped"},
+		{name: "error", fallback: Fallback{Missing: MissingError}, char: '€', err: true},
+		{name: "unknown fallback font", fallback: Fallback{Fonts: []string{"huge"}}, char: '€', err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			columns, substitution, err := tc.fallback.Glyph(font, tc.char)
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error, got %b", columns)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(columns, tc.expected) {
+				t.Errorf("expected columns %b, got %b", tc.expected, columns)
+			}
+			if (substitution == nil) != (tc.substitution == "") {
+				t.Fatalf("expected substitution %q, got %v", tc.substitution, substitution)
+			}
+			if substitution != nil && substitution.String() != tc.substitution {
+				t.Errorf("expected substitution %q, got %q", tc.substitution, substitution.String())
+			}
+		})
+	}
+}
+
+func TestParseMissingStrategy(t *testing.T) {
+	strategy, err := ParseMissingStrategy("skip")
+	if err != nil || strategy != MissingSkip {
+		t.Errorf("expected MissingSkip, got %v, %v", strategy, err)
+	}
+	if _, err := ParseMissingStrategy("ignore"); err == nil {
+		t.Errorf("expected an error for an unknown strategy")
+	}
+}
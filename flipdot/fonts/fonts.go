@@ -2,31 +2,170 @@ package fonts
 
 import (
 	"fmt"
-	"unicode"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
+const (
+	// rows is the number of rows GetCharacter positions the characters in
+	rows = 14
+	// maxHeight is the highest font that fits in the uint16 columns
+	maxHeight = 16
+)
+
+// Font is a bitmap font that text can be drawn with
+type Font interface {
+	// Name is the name the font is registered with
+	Name() string
+	// Glyph returns the columns of a character, the lowest bit is the top row of the font.
+	// ok is false if the font does not have the character.
+	Glyph(char rune) (columns []uint16, ok bool)
+	// Height is the number of rows of the font, at most 16
+	Height() int
+	// Baseline is the row the characters sit on, counted from the top row
+	Baseline() int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Font{}
+)
+
+func init() {
+	// the small font is drawn between rows 2 and 11 of the 14 rows
+	Register(NewBitmapFont("small", 10, 7, shiftUp(characters5x8, 2)))
+	Register(NewBitmapFont("large", 14, 13, characters14x9))
+	Register(NewBitmapFont("condensed", 7, 6, characters3x7))
+	Register(NewBitmapFont("mini", 5, 4, withLowercase(characters3x5)))
+	Register(NewBitmapFont("tall", 14, 13, characters5x14))
+}
+
+// Register makes a font available by its name, the name must not be in use already
+func Register(font Font) error {
+	if font.Height() < 1 || font.Height() > maxHeight {
+		return fmt.Errorf("font '%s' is %d pixels high, it must be between 1 and %d", font.Name(), font.Height(), maxHeight)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[font.Name()]; ok {
+		return fmt.Errorf("font '%s' is already registered", font.Name())
+	}
+	registry[font.Name()] = font
+	return nil
+}
+
+// unregister removes a font and its spacing from the registry, so tests can register their fonts again
+func unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, name)
+	delete(spacings, name)
+}
+
+// Lookup returns the font registered with the name
+func Lookup(name string) (Font, error) {
+	registryMu.RLock()
+	font, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("font '%s' not found, must be one of '%s'", name, strings.Join(Names(), "', '"))
+	}
+	return font, nil
+}
+
+// Names returns the names of all registered fonts in alphabetical order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetCharacter returns the columns of a character in a registered font.
+// Fonts lower than 14 rows are centered in 14 rows, like the large font.
 func GetCharacter(char rune, size string) ([]uint16, error) {
-	if size == "small" {
-		charData, ok := characters5x8[char]
-		if !ok {
-			return nil, fmt.Errorf("character '%c' in size 'small' not found", char)
-		}
+	font, err := Lookup(size)
+	if err != nil {
+		return nil, err
+	}
 
+	charData, ok := font.Glyph(char)
+	if !ok {
+		return nil, fmt.Errorf("character '%c' in size '%s' not found", char, size)
+	}
+
+	offset := max(rows-font.Height(), 0) / 2
+	if offset == 0 {
 		return charData, nil
 	}
+	shifted := make([]uint16, len(charData))
+	for i, column := range charData {
+		shifted[i] = column << offset
+	}
+	return shifted, nil
+}
 
-	if size == "large" {
-		// Until I write all the lower letters in this bigger font
-		if unicode.IsLower(char) && unicode.IsLetter(char) {
-			char = unicode.ToUpper(char)
-		}
+// LoadFont reads a BDF or PSF font file and registers it.
+// The font is named after the file without its extension, e.g. "fonts/6x13.bdf" is "6x13".
+func LoadFont(path string) (*BitmapFont, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open font: %v", err)
+	}
+	defer f.Close()
 
-		charData, ok := characters14x9[char]
-		if !ok {
-			return nil, fmt.Errorf("character '%c' in size 'large' not found", char)
+	var font *BitmapFont
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".bdf":
+		font, err = ParseBDF(f)
+	case ".psf", ".psfu":
+		font, err = ParsePSF(f)
+	default:
+		return nil, fmt.Errorf("font file %s not supported, must end with '.bdf', '.psf' or '.psfu'", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font %s: %v", path, err)
+	}
+
+	font.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := Register(font); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// shiftUp moves every column of the characters up by offset rows
+func shiftUp(characters map[rune][]uint16, offset int) map[rune][]uint16 {
+	shifted := map[rune][]uint16{}
+	for char, columns := range characters {
+		shifted[char] = make([]uint16, len(columns))
+		for i, column := range columns {
+			shifted[char][i] = column >> offset
 		}
-		return charData, nil
 	}
+	return shifted
+}
 
-	return nil, fmt.Errorf("size '%s' not supported, must be 'small' or 'large'", size)
+// withLowercase adds the capitals of the characters as their lowercase letters too, for fonts too small for both
+func withLowercase(characters map[rune][]uint16) map[rune][]uint16 {
+	result := map[rune][]uint16{}
+	for char, columns := range characters {
+		result[char] = columns
+		if char >= 'A' && char <= 'Z' {
+			result[char-'A'+'a'] = columns
+		}
+	}
+	return result
 }
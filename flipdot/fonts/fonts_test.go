@@ -0,0 +1,163 @@
+package fonts
+
+import (
+	"reflect"
+	"testing"
+)
+
+var sizes = []string{"small", "large"}
+
+// Test that every printable ASCII character can be shown in every size
+func TestPrintableASCII(t *testing.T) {
+	for _, size := range sizes {
+		for char := rune(0x20); char <= 0x7e; char++ {
+			columns, err := GetCharacter(char, size)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				continue
+			}
+			if len(columns) == 0 {
+				t.Errorf("character '%c' in size '%s' has no columns", char, size)
+			}
+			for i, column := range columns {
+				if column >= 1<<14 {
+					t.Errorf("column %d of character '%c' in size '%s' is higher than 14 rows: %b", i, char, size, column)
+				}
+			}
+		}
+	}
+}
+
+// Test that lowercase letters are not shown as capitals
+func TestLargeLowercase(t *testing.T) {
+	lower, err := GetCharacter('g', "large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	upper, err := GetCharacter('G', "large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lower) == len(upper) && lower[0] == upper[0] {
+		t.Error("expected 'g' to have its own glyph")
+	}
+
+	// the descender of 'g' goes below the row lowercase letters sit on
+	descender := false
+	for _, column := range lower {
+		if column&(1<<13) != 0 {
+			descender = true
+		}
+	}
+	if !descender {
+		t.Error("expected 'g' to have a descender")
+	}
+}
+
+func TestUnknownCharacter(t *testing.T) {
+	for _, size := range sizes {
+		if _, err := GetCharacter('€', size); err == nil {
+			t.Errorf("expected error for a character that is not in size '%s'", size)
+		}
+	}
+	if _, err := GetCharacter('A', "huge"); err == nil {
+		t.Error("expected error for an unknown size")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	tiny := NewBitmapFont("test-registry", 3, 2, map[rune][]uint16{'-': {0b010, 0b010}})
+	if err := Register(tiny); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregister("test-registry") })
+	if err := Register(tiny); err == nil {
+		t.Error("expected error for registering a name twice")
+	}
+	if err := Register(NewBitmapFont("test-too-high", 17, 16, nil)); err == nil {
+		t.Error("expected error for a font higher than 16 rows")
+	}
+
+	font, err := Lookup("test-registry")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if font != tiny {
+		t.Errorf("expected the registered font, got %v", font)
+	}
+	if _, err := Lookup("test-missing"); err == nil {
+		t.Error("expected error for a font that is not registered")
+	}
+
+	// GetCharacter centers the 3 row font in 14 rows
+	columns, err := GetCharacter('-', "test-registry")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if columns[0] != 0b010<<5 {
+		t.Errorf("expected the character to be centered, got %b", columns[0])
+	}
+}
+
+// Test that the characters of the built in fonts fit in the rows the fonts say they have
+func TestBuiltInFontHeight(t *testing.T) {
+	for _, size := range sizes {
+		font, err := Lookup(size)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for char := rune(0x20); char <= 0x7e; char++ {
+			columns, _ := font.Glyph(char)
+			for _, column := range columns {
+				if column >= 1<<font.Height() {
+					t.Errorf("character '%c' in font '%s' is higher than %d rows", char, size, font.Height())
+				}
+			}
+		}
+		// capital letters sit on the baseline
+		columns, _ := font.Glyph('L')
+		if columns[0]&(1<<font.Baseline()) == 0 || columns[0]>>(font.Baseline()+1) != 0 {
+			t.Errorf("expected 'L' in font '%s' to end on the baseline row %d, got %b", size, font.Baseline(), columns[0])
+		}
+	}
+}
+
+// Test that the mini font draws lowercase letters as capitals
+func TestMiniLowercase(t *testing.T) {
+	font, err := Lookup("mini")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for char := 'A'; char <= 'Z'; char++ {
+		upper, _ := font.Glyph(char)
+		lower, ok := font.Glyph(char - 'A' + 'a')
+		if !ok || !reflect.DeepEqual(upper, lower) {
+			t.Errorf("expected '%c' to be drawn as '%c', got %b", char-'A'+'a', char, lower)
+		}
+	}
+	columns, _ := font.Glyph('L')
+	if columns[0] != 0b11111 || columns[2] != 1<<font.Baseline() {
+		t.Errorf("expected 'L' to end on the baseline row %d, got %b", font.Baseline(), columns)
+	}
+}
+
+// Test that the tall digits use every row of the font
+func TestTallDigits(t *testing.T) {
+	font, err := Lookup("tall")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for char := '0'; char <= '9'; char++ {
+		columns, ok := font.Glyph(char)
+		if !ok || len(columns) != 5 {
+			t.Fatalf("expected '%c' to be 5 columns wide, got %b", char, columns)
+		}
+		var rows uint16
+		for _, column := range columns {
+			rows |= column
+		}
+		if rows != 1<<font.Height()-1 {
+			t.Errorf("expected '%c' to use all %d rows, got %b", char, font.Height(), rows)
+		}
+	}
+}
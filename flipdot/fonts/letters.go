@@ -73,9 +73,57 @@ var characters5x8 = map[rune][]uint16{
 	'Z': {0b00001100001000, 0b00001010001000, 0b00001001001000, 0b00001000101000, 0b00001000011000},
 	' ': {0b00000000000000, 0b00000000000000, 0b00000000000000, 0b00000000000000, 0b00000000000000},
 	'.': {0b00010000000000},
+
+	// Punctuation and symbols
+	'!':  {0b00001011111000},
+	'"':  {0b00000000011000, 0b00000000000000, 0b00000000011000},
+	'#':  {0b00000010100000, 0b00001111111000, 0b00000010100000, 0b00001111111000, 0b00000010100000},
+	'$':  {0b00000100100000, 0b00000101010000, 0b00001111111000, 0b00000101010000, 0b00000010010000},
+	'%':  {0b00000100011000, 0b00000010011000, 0b00000001000000, 0b00001100100000, 0b00001100010000},
+	'&':  {0b00000110110000, 0b00001001001000, 0b00001010101000, 0b00000100010000, 0b00001010000000},
+	'\'': {0b00000000011000},
+	'(':  {0b00000011100000, 0b00000100010000, 0b00001000001000},
+	')':  {0b00001000001000, 0b00000100010000, 0b00000011100000},
+	'*':  {0b00000010100000, 0b00000001000000, 0b00000111110000, 0b00000001000000, 0b00000010100000},
+	'+':  {0b00000001000000, 0b00000001000000, 0b00000111110000, 0b00000001000000, 0b00000001000000},
+	',':  {0b00010000000000, 0b00001100000000},
+	'-':  {0b00000001000000, 0b00000001000000, 0b00000001000000, 0b00000001000000},
+	'/':  {0b00001000000000, 0b00000110000000, 0b00000001000000, 0b00000000110000, 0b00000000001000},
+	';':  {0b00011100110000, 0b00001100110000},
+	'<':  {0b00000001000000, 0b00000010100000, 0b00000100010000, 0b00001000001000},
+	'=':  {0b00000010100000, 0b00000010100000, 0b00000010100000, 0b00000010100000},
+	'>':  {0b00001000001000, 0b00000100010000, 0b00000010100000, 0b00000001000000},
+	'?':  {0b00000000010000, 0b00000000001000, 0b00001011001000, 0b00000001001000, 0b00000000110000},
+	'@':  {0b00000111110000, 0b00001000001000, 0b00001011101000, 0b00001010101000, 0b00000011110000},
+	'[':  {0b00001111111000, 0b00001000001000, 0b00001000001000},
+	'\\': {0b00000000001000, 0b00000000110000, 0b00000001000000, 0b00000110000000, 0b00001000000000},
+	']':  {0b00001000001000, 0b00001000001000, 0b00001111111000},
+	'^':  {0b00000000100000, 0b00000000010000, 0b00000000001000, 0b00000000010000, 0b00000000100000},
+	'_':  {0b00010000000000, 0b00010000000000, 0b00010000000000, 0b00010000000000, 0b00010000000000},
+	'`':  {0b00000000001000, 0b00000000010000},
+	'{':  {0b00000001000000, 0b00000110110000, 0b00001000001000, 0b00001000001000},
+	'|':  {0b00011111111100},
+	'}':  {0b00001000001000, 0b00001000001000, 0b00000110110000, 0b00000001000000},
+	'~':  {0b00000001000000, 0b00000000100000, 0b00000001000000, 0b00000010000000, 0b00000001000000},
 }
 
+// characters14x9 is a 14-pixel high font with strokes 2 pixels wide.
+// Capitals, numbers and symbols use all 14 rows. Lowercase letters sit 3 rows higher, on row 10,
+// so that g, j, p, q and y have room for their descenders.
 var characters14x9 = map[rune][]uint16{
+	// Numbers
+	'0': {0b01111111111110, 0b11111111111111, 0b11000000000011, 0b11000000000011, 0b11000000000011, 0b11000000000011, 0b11111111111111, 0b01111111111110},
+	'1': {0b11000000001100, 0b11000000000110, 0b11111111111111, 0b11111111111111, 0b11000000000000, 0b11000000000000},
+	'2': {0b11111100000110, 0b11111110000111, 0b11000110000011, 0b11000011000011, 0b11000011000011, 0b11000001100011, 0b11000001111111, 0b11000000111110},
+	'3': {0b01100000000110, 0b11100000000111, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11111111111111, 0b01111111111110},
+	'4': {0b00000111100000, 0b00000111110000, 0b00000110011000, 0b00000110001100, 0b00000110000110, 0b11111111111111, 0b11111111111111, 0b00000110000000},
+	'5': {0b01100001111111, 0b11100001111111, 0b11000001100011, 0b11000001100011, 0b11000001100011, 0b11000001100011, 0b11111111100011, 0b01111111000011},
+	'6': {0b01111111111110, 0b11111111111111, 0b11000001100011, 0b11000001100011, 0b11000001100011, 0b11000001100011, 0b11111111100111, 0b01111111000110},
+	'7': {0b00000000000011, 0b00000000000011, 0b00000000000011, 0b11111100000011, 0b11111111000011, 0b00000011110011, 0b00000000111111, 0b00000000001111},
+	'8': {0b01111100111110, 0b11111111111111, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11111111111111, 0b01111100111110},
+	'9': {0b01100011111110, 0b11100111111111, 0b11000110000011, 0b11000110000011, 0b11000110000011, 0b11000110000011, 0b11111111111111, 0b01111111111110},
+
+	// Uppercase letters
 	'A': {0b11111111111100, 0b11111111111110, 0b00000110000111, 0b00000110000011, 0b00000110000011, 0b00000110000011, 0b00000110000111, 0b11111111111110, 0b11111111111100},
 	'B': {0b11111111111111, 0b11111111111111, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11000011000011, 0b11100111100111, 0b01111110111110, 0b00111100011100},
 	'C': {0b00111111111100, 0b01111111111110, 0b11100000000111, 0b11000000000011, 0b11000000000011, 0b11000000000011, 0b11000000000011, 0b11100000000111, 0b01100000000110},
@@ -104,4 +152,165 @@ var characters14x9 = map[rune][]uint16{
 	'Z': {0b11110000000011, 0b11111000000011, 0b11011100000011, 0b11001110000011, 0b11000111000011, 0b11000011100011, 0b11000001110011, 0b11000000011111, 0b11000000001111},
 	' ': {0b00000000000000, 0b00000000000000, 0b00000000000000, 0b00000000000000, 0b00000000000000},
 	'.': {0b11000000000000, 0b11000000000000},
+
+	// Lowercase letters
+	'a': {0b00001110000000, 0b00011111011000, 0b00011011011000, 0b00011011011000, 0b00011011011000, 0b00011111111000, 0b00011111110000},
+	'b': {0b00011111111111, 0b00011111111111, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011111111000, 0b00001111110000},
+	'c': {0b00001111110000, 0b00011111111000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011000011000},
+	'd': {0b00001111110000, 0b00011111111000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011111111111, 0b00011111111111},
+	'e': {0b00001111110000, 0b00011111111000, 0b00011011011000, 0b00011011011000, 0b00011011011000, 0b00011011111000, 0b00011011110000},
+	'f': {0b00000000011000, 0b00000000011000, 0b00011111111110, 0b00011111111111, 0b00000000011011, 0b00000000011011},
+	'g': {0b11001111110000, 0b11011111111000, 0b11011000011000, 0b11011000011000, 0b11011000011000, 0b11111111111000, 0b01111111111000},
+	'h': {0b00011111111111, 0b00011111111111, 0b00000000011000, 0b00000000011000, 0b00000000011000, 0b00011111111000, 0b00011111110000},
+	'i': {0b00011111111011, 0b00011111111011},
+	'j': {0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11111111111011, 0b01111111111011},
+	'k': {0b00011111111111, 0b00011111111111, 0b00000011000000, 0b00000111100000, 0b00001100110000, 0b00011000011000, 0b00010000001000},
+	'l': {0b00001111111111, 0b00011111111111, 0b00011000000000},
+	'm': {0b00011111111000, 0b00011111111000, 0b00000000011000, 0b00000000011000, 0b00011111111000, 0b00011111111000, 0b00000000011000, 0b00000000011000, 0b00011111111000, 0b00011111110000},
+	'n': {0b00011111111000, 0b00011111111000, 0b00000000011000, 0b00000000011000, 0b00000000011000, 0b00011111111000, 0b00011111110000},
+	'o': {0b00001111110000, 0b00011111111000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011111111000, 0b00001111110000},
+	'p': {0b11111111111000, 0b11111111111000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b00011111111000, 0b00001111110000},
+	'q': {0b00001111110000, 0b00011111111000, 0b00011000011000, 0b00011000011000, 0b00011000011000, 0b11111111111000, 0b11111111111000},
+	'r': {0b00011111111000, 0b00011111111000, 0b00000000110000, 0b00000000011000, 0b00000000011000, 0b00000000011000},
+	's': {0b00011001110000, 0b00011011111000, 0b00011011011000, 0b00011011011000, 0b00011011011000, 0b00011111011000, 0b00001110011000},
+	't': {0b00000000011000, 0b00001111111111, 0b00011111111111, 0b00011000011000, 0b00011000011000, 0b00011000011000},
+	'u': {0b00001111111000, 0b00011111111000, 0b00011000000000, 0b00011000000000, 0b00011000000000, 0b00011111111000, 0b00011111111000},
+	'v': {0b00000001111000, 0b00000111111000, 0b00001110000000, 0b00011100000000, 0b00001110000000, 0b00000111111000, 0b00000001111000},
+	'w': {0b00001111111000, 0b00011111111000, 0b00011000000000, 0b00011000000000, 0b00001111111000, 0b00001111111000, 0b00011000000000, 0b00011000000000, 0b00011111111000, 0b00001111111000},
+	'x': {0b00011000011000, 0b00011100111000, 0b00000111100000, 0b00000011000000, 0b00000111100000, 0b00011100111000, 0b00011000011000},
+	'y': {0b11001111111000, 0b11011111111000, 0b11011000000000, 0b11011000000000, 0b11011000000000, 0b11111111111000, 0b01111111111000},
+	'z': {0b00011000011000, 0b00011100011000, 0b00011110011000, 0b00011011011000, 0b00011001111000, 0b00011000111000, 0b00011000011000},
+
+	// Punctuation and symbols
+	'!':  {0b11001111111111, 0b11001111111111},
+	'"':  {0b00000000001111, 0b00000000001111, 0b00000000000000, 0b00000000001111, 0b00000000001111},
+	'#':  {0b00011000011000, 0b00011000011000, 0b11111111111111, 0b11111111111111, 0b00011000011000, 0b00011000011000, 0b11111111111111, 0b11111111111111, 0b00011000011000, 0b00011000011000},
+	'$':  {0b00010000111100, 0b00110001111110, 0b00110001100110, 0b11111111111111, 0b11111111111111, 0b00110001100110, 0b00111111100110, 0b00011111000100},
+	'%':  {0b11000000000011, 0b11110000000011, 0b00111100000000, 0b00001110000000, 0b00000011000000, 0b00000001110000, 0b00000000111100, 0b11000000001111, 0b11000000000011},
+	'&':  {0b01111111001110, 0b11111111111111, 0b11000001110011, 0b11000011110011, 0b01100111011111, 0b00111110001110, 0b01111100000000, 0b11111111000000, 0b11100111000000},
+	'\'': {0b00000000001111, 0b00000000001111},
+	'(':  {0b00011111111000, 0b01111111111110, 0b11100000000111, 0b10000000000001},
+	')':  {0b10000000000001, 0b11100000000111, 0b01111111111110, 0b00011111111000},
+	'*':  {0b00000100010000, 0b00000110110000, 0b00000011100000, 0b00001111111000, 0b00001111111000, 0b00000011100000, 0b00000110110000, 0b00000100010000},
+	'+':  {0b00000011000000, 0b00000011000000, 0b00000011000000, 0b00011111111000, 0b00011111111000, 0b00000011000000, 0b00000011000000, 0b00000011000000},
+	',':  {0b10000000000000, 0b11100000000000, 0b01100000000000},
+	'-':  {0b00000011000000, 0b00000011000000, 0b00000011000000, 0b00000011000000, 0b00000011000000, 0b00000011000000},
+	'/':  {0b11000000000000, 0b11111000000000, 0b00111110000000, 0b00000111100000, 0b00000001111100, 0b00000000011111, 0b00000000000011},
+	':':  {0b00011000011000, 0b00011000011000},
+	';':  {0b01000000000000, 0b01111000011000, 0b00111000011000},
+	'<':  {0b00000011000000, 0b00000111100000, 0b00001100110000, 0b00011000011000, 0b00110000001100, 0b00100000000100},
+	'=':  {0b00001100110000, 0b00001100110000, 0b00001100110000, 0b00001100110000, 0b00001100110000, 0b00001100110000, 0b00001100110000},
+	'>':  {0b00100000000100, 0b00110000001100, 0b00011000011000, 0b00001100110000, 0b00000111100000, 0b00000011000000},
+	'?':  {0b00000000000110, 0b00000000000111, 0b00000000000011, 0b11001111000011, 0b11001111000011, 0b00000011100011, 0b00000001111111, 0b00000000111110},
+	'@':  {0b00111111111100, 0b01111111111110, 0b11000000000011, 0b10000011110001, 0b10000111111001, 0b10000100001001, 0b10000100001001, 0b11000111111011, 0b01100100000110, 0b00100111111100},
+	'[':  {0b11111111111111, 0b11111111111111, 0b11000000000011, 0b11000000000011},
+	'\\': {0b00000000000011, 0b00000000011111, 0b00000001111100, 0b00000111100000, 0b00111110000000, 0b11111000000000, 0b11000000000000},
+	']':  {0b11000000000011, 0b11000000000011, 0b11111111111111, 0b11111111111111},
+	'^':  {0b00000000001000, 0b00000000001100, 0b00000000000110, 0b00000000000011, 0b00000000000011, 0b00000000000110, 0b00000000001100, 0b00000000001000},
+	'_':  {0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11000000000000, 0b11000000000000},
+	'`':  {0b00000000000011, 0b00000000000111, 0b00000000000110},
+	'{':  {0b00000011000000, 0b01111111111110, 0b11111100111111, 0b10000000000001, 0b10000000000001},
+	'|':  {0b11111111111111, 0b11111111111111},
+	'}':  {0b10000000000001, 0b10000000000001, 0b11111100111111, 0b01111111111110, 0b00000011000000},
+	'~':  {0b00000110000000, 0b00000011000000, 0b00000001100000, 0b00000001100000, 0b00000011000000, 0b00000110000000, 0b00000110000000, 0b00000011000000},
+}
+
+// characters3x7 is a 7-pixel high font with digits 3 pixels wide, so that times with seconds fit in 28 columns
+var characters3x7 = map[rune][]uint16{
+	// Numbers
+	'0': {0b1111111, 0b1000001, 0b1111111},
+	'1': {0b1000010, 0b1111111, 0b1000000},
+	'2': {0b1111001, 0b1001001, 0b1001111},
+	'3': {0b1001001, 0b1001001, 0b1111111},
+	'4': {0b0001111, 0b0001000, 0b1111111},
+	'5': {0b1001111, 0b1001001, 0b1111001},
+	'6': {0b1111111, 0b1001001, 0b1111001},
+	'7': {0b0000001, 0b1111001, 0b0000111},
+	'8': {0b1111111, 0b1001001, 0b1111111},
+	'9': {0b1001111, 0b1001001, 0b1111111},
+
+	// Letters for AM and PM
+	'A': {0b1111110, 0b0001001, 0b1111110},
+	'P': {0b1111111, 0b0001001, 0b0000110},
+	'M': {0b1111111, 0b0000110, 0b1111111},
+
+	// Punctuation
+	':': {0b0010100},
+	'.': {0b1000000},
+	'-': {0b0001000, 0b0001000},
+	'/': {0b1100000, 0b0011100, 0b0000011},
+	' ': {0b0000000, 0b0000000},
+}
+
+// characters3x5 is a 5-pixel high font 3 pixels wide for a second row of text under the time, e.g. the date.
+// Lowercase letters are drawn the same as capitals, see withLowercase.
+var characters3x5 = map[rune][]uint16{
+	// Numbers
+	'0': {0b11111, 0b10001, 0b11111},
+	'1': {0b10010, 0b11111, 0b10000},
+	'2': {0b11101, 0b10101, 0b10111},
+	'3': {0b10001, 0b10101, 0b11111},
+	'4': {0b00111, 0b00100, 0b11111},
+	'5': {0b10111, 0b10101, 0b11101},
+	'6': {0b11111, 0b10101, 0b11101},
+	'7': {0b00001, 0b11101, 0b00011},
+	'8': {0b11111, 0b10101, 0b11111},
+	'9': {0b10111, 0b10101, 0b11111},
+
+	// Letters
+	'A': {0b11110, 0b00101, 0b11110},
+	'B': {0b11111, 0b10101, 0b01010},
+	'C': {0b01110, 0b10001, 0b10001},
+	'D': {0b11111, 0b10001, 0b01110},
+	'E': {0b11111, 0b10101, 0b10001},
+	'F': {0b11111, 0b00101, 0b00001},
+	'G': {0b01110, 0b10001, 0b11101},
+	'H': {0b11111, 0b00100, 0b11111},
+	'I': {0b10001, 0b11111, 0b10001},
+	'J': {0b01000, 0b10000, 0b01111},
+	'K': {0b11111, 0b00100, 0b11011},
+	'L': {0b11111, 0b10000, 0b10000},
+	'M': {0b11111, 0b00110, 0b11111},
+	'N': {0b11111, 0b00001, 0b11110},
+	'O': {0b01110, 0b10001, 0b01110},
+	'P': {0b11111, 0b00101, 0b00010},
+	'Q': {0b01110, 0b11001, 0b10110},
+	'R': {0b11111, 0b00101, 0b11010},
+	'S': {0b10010, 0b10101, 0b01001},
+	'T': {0b00001, 0b11111, 0b00001},
+	'U': {0b11111, 0b10000, 0b11111},
+	'V': {0b01111, 0b10000, 0b01111},
+	'W': {0b11111, 0b01100, 0b11111},
+	'X': {0b11011, 0b00100, 0b11011},
+	'Y': {0b00011, 0b11100, 0b00011},
+	'Z': {0b11001, 0b10101, 0b10011},
+
+	// Punctuation
+	':': {0b01010},
+	'.': {0b10000},
+	',': {0b11000},
+	'-': {0b00100, 0b00100},
+	'/': {0b11000, 0b00100, 0b00011},
+	' ': {0b00000, 0b00000},
+}
+
+// characters5x14 are digits the full height of the display 5 pixels wide, so that "15:04" fills it
+var characters5x14 = map[rune][]uint16{
+	// Numbers
+	'0': {0b01111111111110, 0b11111111111111, 0b11000000000011, 0b11111111111111, 0b01111111111110},
+	'1': {0b11000000000100, 0b11000000000110, 0b11111111111111, 0b11111111111111, 0b11000000000000},
+	'2': {0b11111100000110, 0b11111110000111, 0b11000111000011, 0b11000011111111, 0b11000001111110},
+	'3': {0b01100000000110, 0b11100011000111, 0b11000011000011, 0b11111111111111, 0b01111100111110},
+	'4': {0b00000111111000, 0b00000111111100, 0b00000110000110, 0b11111111111111, 0b11111111111111},
+	'5': {0b01100001111111, 0b11100001111111, 0b11000001100011, 0b11111111100011, 0b01111111000011},
+	'6': {0b01111111111110, 0b11111111111111, 0b11000011000011, 0b11111111000111, 0b01111110000110},
+	'7': {0b00000000000011, 0b11111100000011, 0b11111111100011, 0b00000011111111, 0b00000000011111},
+	'8': {0b01111100111110, 0b11111111111111, 0b11000011000011, 0b11111111111111, 0b01111100111110},
+	'9': {0b01100001111110, 0b11100011111111, 0b11000011000011, 0b11111111111111, 0b01111111111110},
+
+	// Punctuation
+	':': {0b00001100110000, 0b00001100110000},
+	'.': {0b11000000000000, 0b11000000000000},
+	'-': {0b00000011000000, 0b00000011000000, 0b00000011000000},
+	' ': {0b00000000000000, 0b00000000000000},
 }
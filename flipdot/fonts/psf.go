@@ -0,0 +1,212 @@
+package fonts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var (
+	psf1Magic = []byte{0x36, 0x04}
+	psf2Magic = []byte{0x72, 0xb5, 0x4a, 0x86}
+)
+
+const (
+	psf1ModeHas512 = 0x01
+	psf1ModeHasTab = 0x02
+	psf1ModeSeq    = 0x04
+	psf2HasTable   = 0x01
+
+	// psfMaxWidth is the widest PSF font that is read, the widest console fonts are 32 pixels wide
+	psfMaxWidth = 32
+)
+
+// ParsePSF reads a PC Screen Font, version 1 or 2, as used by the Linux console
+// Fonts without a Unicode table are assumed to be in the Latin-1 order.
+func ParsePSF(r io.Reader) (*BitmapFont, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %v", err)
+	}
+
+	var glyphs [][]byte
+	var width, height int
+	var table []byte
+	var tableUTF8 bool
+
+	switch {
+	case bytes.HasPrefix(data, psf2Magic):
+		if len(data) < 32 {
+			return nil, fmt.Errorf("PSF2 header is too short")
+		}
+		header := struct {
+			Version, HeaderSize, Flags, Length, CharSize, Height, Width uint32
+		}{}
+		if err := binary.Read(bytes.NewReader(data[4:32]), binary.LittleEndian, &header); err != nil {
+			return nil, fmt.Errorf("invalid PSF2 header: %v", err)
+		}
+		width, height = int(header.Width), int(header.Height)
+		if err := checkPSFSize(width, height); err != nil {
+			return nil, err
+		}
+		if header.CharSize != uint32((width+7)/8*height) {
+			return nil, fmt.Errorf("invalid PSF2 header, %d bytes per character is wrong for %dx%d", header.CharSize, width, height)
+		}
+		glyphs, table, err = psfGlyphs(data, int(header.HeaderSize), int(header.Length), int(header.CharSize))
+		if err != nil {
+			return nil, err
+		}
+		if header.Flags&psf2HasTable == 0 {
+			table = nil
+		}
+		tableUTF8 = true
+	case bytes.HasPrefix(data, psf1Magic):
+		if len(data) < 4 {
+			return nil, fmt.Errorf("PSF1 header is too short")
+		}
+		mode := data[2]
+		width, height = 8, int(data[3])
+		if err := checkPSFSize(width, height); err != nil {
+			return nil, err
+		}
+		length := 256
+		if mode&psf1ModeHas512 != 0 {
+			length = 512
+		}
+		glyphs, table, err = psfGlyphs(data, 4, length, height)
+		if err != nil {
+			return nil, err
+		}
+		if mode&(psf1ModeHasTab|psf1ModeSeq) == 0 {
+			table = nil
+		}
+	default:
+		return nil, fmt.Errorf("not a PSF font")
+	}
+
+	characters := map[rune][]uint16{}
+	if table == nil {
+		for i, glyph := range glyphs {
+			characters[rune(i)] = psfColumns(glyph, width, height)
+		}
+		return NewBitmapFont("", height, psfBaseline(characters, height), characters), nil
+	}
+
+	runes, err := psfUnicodeTable(table, len(glyphs), tableUTF8)
+	if err != nil {
+		return nil, err
+	}
+	for i, glyphRunes := range runes {
+		for _, r := range glyphRunes {
+			characters[r] = psfColumns(glyphs[i], width, height)
+		}
+	}
+	return NewBitmapFont("", height, psfBaseline(characters, height), characters), nil
+}
+
+// checkPSFSize returns an error for glyph sizes that are not supported, before the glyphs are read with them
+func checkPSFSize(width int, height int) error {
+	if height < 1 || height > maxHeight {
+		return fmt.Errorf("font is %d pixels high, at most %d are supported", height, maxHeight)
+	}
+	if width < 1 || width > psfMaxWidth {
+		return fmt.Errorf("font is %d pixels wide, at most %d are supported", width, psfMaxWidth)
+	}
+	return nil
+}
+
+// psfBaseline returns the bottom row of 'H' as PSF fonts do not say where their baseline is
+func psfBaseline(characters map[rune][]uint16, height int) int {
+	baseline := -1
+	for _, column := range characters['H'] {
+		for row := range height {
+			if column&(1<<row) != 0 {
+				baseline = max(baseline, row)
+			}
+		}
+	}
+	if baseline < 0 {
+		return height - 1
+	}
+	return baseline
+}
+
+// psfGlyphs splits the font data into the glyphs and the Unicode table after them
+func psfGlyphs(data []byte, offset int, length int, charSize int) ([][]byte, []byte, error) {
+	// the sizes come from the header, so they are compared by division which can not overflow
+	if offset < 0 || offset > len(data) || length < 1 || charSize < 1 || length > (len(data)-offset)/charSize {
+		return nil, nil, fmt.Errorf("PSF font is truncated, %d characters of %d bytes do not fit", length, charSize)
+	}
+	end := offset + length*charSize
+	var glyphs [][]byte
+	for i := range length {
+		glyphs = append(glyphs, data[offset+i*charSize:offset+(i+1)*charSize])
+	}
+	return glyphs, data[end:], nil
+}
+
+// psfColumns converts a glyph stored as rows of bits to one uint16 per column, the lowest bit is the top row
+func psfColumns(glyph []byte, width int, height int) []uint16 {
+	rowBytes := (width + 7) / 8
+	columns := make([]uint16, width)
+	for y := range height {
+		for x := range width {
+			if glyph[y*rowBytes+x/8]&(0x80>>(x%8)) != 0 {
+				columns[x] |= 1 << y
+			}
+		}
+	}
+	return trimColumns(columns, width)
+}
+
+// psfUnicodeTable returns the characters each glyph is used for.
+// The table has an entry per glyph ending with 0xFFFF in PSF1 or 0xFF in PSF2. Sequences
+// of combining characters start with 0xFFFE or 0xFE and are skipped, as only single characters are looked up.
+func psfUnicodeTable(table []byte, length int, utf8Table bool) ([][]rune, error) {
+	runes := make([][]rune, length)
+	for i := range length {
+		inSequence := false
+		for {
+			var value rune
+			var terminator, sequence bool
+			switch {
+			case utf8Table && len(table) > 0:
+				switch table[0] {
+				case 0xff:
+					terminator = true
+					table = table[1:]
+				case 0xfe:
+					sequence = true
+					table = table[1:]
+				default:
+					r, size := utf8.DecodeRune(table)
+					if r == utf8.RuneError {
+						return nil, fmt.Errorf("invalid UTF-8 in PSF Unicode table at character %d", i)
+					}
+					value = r
+					table = table[size:]
+				}
+			case !utf8Table && len(table) >= 2:
+				v := binary.LittleEndian.Uint16(table)
+				terminator = v == 0xffff
+				sequence = v == 0xfffe
+				value = rune(v)
+				table = table[2:]
+			default:
+				return nil, fmt.Errorf("PSF Unicode table is truncated at character %d", i)
+			}
+
+			if terminator {
+				break
+			}
+			if sequence {
+				inSequence = true
+			} else if !inSequence {
+				runes[i] = append(runes[i], value)
+			}
+		}
+	}
+	return runes, nil
+}
@@ -0,0 +1,83 @@
+package fonts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// psfGlyph is a 3x4 glyph with the left column and the top row set
+var psfGlyph = []byte{0b11100000, 0b10000000, 0b10000000, 0b10000000}
+
+func TestParsePSF1(t *testing.T) {
+	// 256 characters of 8x4 without a Unicode table
+	data := append([]byte{0x36, 0x04, 0x00, 4}, make([]byte, 256*4)...)
+	copy(data[4+'L'*4:], psfGlyph)
+
+	font, err := ParsePSF(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if font.height != 4 || len(font.characters) != 256 {
+		t.Fatalf("expected 256 characters 4 high, got %d %d high", len(font.characters), font.height)
+	}
+	if !reflect.DeepEqual(font.characters['L'], []uint16{0b1111, 0b0001, 0b0001}) {
+		t.Errorf("unexpected columns: %b", font.characters['L'])
+	}
+}
+
+func TestParsePSF2(t *testing.T) {
+	header := []uint32{0, 32, psf2HasTable, 2, 4, 4, 3}
+	data := bytes.NewBuffer([]byte{0x72, 0xb5, 0x4a, 0x86})
+	binary.Write(data, binary.LittleEndian, header)
+	data.Write(make([]byte, 4))
+	data.Write(psfGlyph)
+	// glyph 0 has no characters, glyph 1 is used for 'Γ' and a sequence that is skipped
+	data.WriteByte(0xff)
+	data.WriteString("Γ")
+	data.WriteByte(0xfe)
+	data.WriteString("Γ́")
+	data.WriteByte(0xff)
+
+	font, err := ParsePSF(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[rune][]uint16{'Γ': {0b1111, 0b0001, 0b0001}}
+	if !reflect.DeepEqual(font.characters, expected) {
+		t.Errorf("unexpected characters: %b", font.characters)
+	}
+}
+
+func TestParsePSFErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "not a PSF font", data: []byte("hello")},
+		{name: "truncated", data: []byte{0x36, 0x04, 0x00, 8, 0xff}},
+		{name: "too high", data: append([]byte{0x36, 0x04, 0x00, 32}, make([]byte, 256*32)...)},
+		{name: "too wide", data: psf2Header(64, 1, 256, 8)},
+		{name: "size overflow", data: psf2Header(1, 0xffffffff, 0xffffffff, 0xffffffff)},
+		{name: "length overflow", data: psf2Header(8, 8, 0x7fffffff, 8)},
+		{name: "truncated unicode table", data: append([]byte{0x36, 0x04, psf1ModeHasTab, 1}, make([]byte, 256)...)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParsePSF(bytes.NewReader(tc.data)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+// psf2Header returns the header of a PSF2 font without a Unicode table and without any glyphs
+func psf2Header(width uint32, height uint32, length uint32, charSize uint32) []byte {
+	data := append([]byte{}, psf2Magic...)
+	for _, value := range []uint32{0, 32, 0, length, charSize, height, width} {
+		data = binary.LittleEndian.AppendUint32(data, value)
+	}
+	return data
+}
@@ -0,0 +1,64 @@
+package fonts
+
+import "fmt"
+
+// Spacing is the space between the characters of a font
+type Spacing struct {
+	// Letter is the number of blank columns between characters
+	Letter int
+	// Kerning is added to Letter for pairs of characters, e.g. {"1:": -1} moves a colon after a 1 one column closer.
+	// Characters closer than 0 columns overlap.
+	Kerning map[string]int
+}
+
+// DefaultSpacing is the spacing of fonts without their own, one blank column between characters
+var DefaultSpacing = Spacing{Letter: 1}
+
+// spacings is the spacing of the fonts that do not use DefaultSpacing, guarded by registryMu
+var spacings = map[string]Spacing{}
+
+// Between returns the number of blank columns between the characters left and right, negative if they overlap
+func (s Spacing) Between(left rune, right rune) int {
+	return s.Letter + s.Kerning[string([]rune{left, right})]
+}
+
+// Validate checks the letter spacing is not negative and every kerning pair is two characters
+func (s Spacing) Validate() error {
+	if s.Letter < 0 {
+		return fmt.Errorf("letter spacing must not be negative, got %d", s.Letter)
+	}
+	for pair := range s.Kerning {
+		if len([]rune(pair)) != 2 {
+			return fmt.Errorf("kerning pair '%s' must be two characters", pair)
+		}
+	}
+	return nil
+}
+
+// SetSpacing changes the spacing of a registered font
+func SetSpacing(name string, spacing Spacing) error {
+	if err := spacing.Validate(); err != nil {
+		return fmt.Errorf("invalid spacing for font '%s': %v", name, err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[name]; !ok {
+		return fmt.Errorf("font '%s' not found", name)
+	}
+	spacings[name] = spacing
+	return nil
+}
+
+// SpacingOf returns the spacing of a registered font, DefaultSpacing if it has not been changed
+func SpacingOf(name string) Spacing {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	spacing, ok := spacings[name]
+	if !ok {
+		return DefaultSpacing
+	}
+	return spacing
+}
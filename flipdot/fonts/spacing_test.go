@@ -0,0 +1,43 @@
+package fonts
+
+import "testing"
+
+func TestSpacing(t *testing.T) {
+	spacing := Spacing{Letter: 2, Kerning: map[string]int{"1:": -1, "T.": -3}}
+	if spacing.Between('1', ':') != 1 || spacing.Between('T', '.') != -1 || spacing.Between('2', ':') != 2 {
+		t.Errorf("unexpected spacing between characters")
+	}
+
+	if SpacingOf("large").Letter != 1 {
+		t.Errorf("expected the default spacing for a font without its own")
+	}
+
+	if err := Register(NewBitmapFont("test-spacing", 7, 6, characters3x7)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregister("test-spacing") })
+	if err := SetSpacing("test-spacing", spacing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if SpacingOf("test-spacing").Between('1', ':') != 1 {
+		t.Errorf("expected the spacing set for the font, got %+v", SpacingOf("test-spacing"))
+	}
+
+	invalid := []struct {
+		name    string
+		font    string
+		spacing Spacing
+	}{
+		{name: "unknown font", font: "huge", spacing: DefaultSpacing},
+		{name: "negative letter spacing", font: "test-spacing", spacing: Spacing{Letter: -1}},
+		{name: "kerning pair of one character", font: "test-spacing", spacing: Spacing{Letter: 1, Kerning: map[string]int{"1": -1}}},
+		{name: "kerning pair of three characters", font: "test-spacing", spacing: Spacing{Letter: 1, Kerning: map[string]int{"1:1": -1}}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetSpacing(tc.font, tc.spacing); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package flipdot
+
+// Frame is an image that can be drawn on and then shown on the display
+// The top left dot is 0,0. Drawing outside of the frame is ignored.
+type Frame struct {
+	width  int
+	height int
+	pixels []bool
+}
+
+// NewFrame creates a blank frame
+func NewFrame(width int, height int) *Frame {
+	return &Frame{
+		width:  width,
+		height: height,
+		pixels: make([]bool, width*height),
+	}
+}
+
+// FrameFromColumns creates a frame from display data in the column format used by DisplayOutput
+func FrameFromColumns(columns []uint32, height int) *Frame {
+	f := NewFrame(len(columns), height)
+	for x, column := range columns {
+		for y := range height {
+			f.SetPixel(x, y, column&(1<<y) != 0)
+		}
+	}
+	return f
+}
+
+// Width returns the width of the frame in dots
+func (f *Frame) Width() int {
+	return f.width
+}
+
+// Height returns the height of the frame in dots
+func (f *Frame) Height() int {
+	return f.height
+}
+
+// SetPixel sets the dot at x,y
+func (f *Frame) SetPixel(x int, y int, on bool) {
+	if x < 0 || y < 0 || x >= f.width || y >= f.height {
+		return
+	}
+	f.pixels[y*f.width+x] = on
+}
+
+// GetPixel returns true if the dot at x,y is set, dots outside of the frame are never set
+func (f *Frame) GetPixel(x int, y int) bool {
+	if x < 0 || y < 0 || x >= f.width || y >= f.height {
+		return false
+	}
+	return f.pixels[y*f.width+x]
+}
+
+// Line draws a line from x0,y0 to x1,y1 including both ends
+func (f *Frame) Line(x0 int, y0 int, x1 int, y1 int, on bool) {
+	// Bresenham's line algorithm
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx := 1
+	if x0 > x1 {
+		sx = -1
+	}
+	sy := 1
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+
+	for {
+		f.SetPixel(x0, y0, on)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// Rect draws the outline of a rectangle with its top left corner at x,y
+func (f *Frame) Rect(x int, y int, width int, height int, on bool) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	f.Line(x, y, x+width-1, y, on)
+	f.Line(x, y+height-1, x+width-1, y+height-1, on)
+	f.Line(x, y, x, y+height-1, on)
+	f.Line(x+width-1, y, x+width-1, y+height-1, on)
+}
+
+// FillRect draws a filled rectangle with its top left corner at x,y
+func (f *Frame) FillRect(x int, y int, width int, height int, on bool) {
+	for row := y; row < y+height; row++ {
+		for col := x; col < x+width; col++ {
+			f.SetPixel(col, row, on)
+		}
+	}
+}
+
+// Circle draws the outline of a circle centered on cx,cy
+func (f *Frame) Circle(cx int, cy int, radius int, on bool) {
+	// midpoint circle algorithm, drawing all eight octants at once
+	x := radius
+	y := 0
+	e := 1 - radius
+
+	for x >= y {
+		for _, p := range [][2]int{{x, y}, {y, x}, {-y, x}, {-x, y}, {-x, -y}, {-y, -x}, {y, -x}, {x, -y}} {
+			f.SetPixel(cx+p[0], cy+p[1], on)
+		}
+		y++
+		if e < 0 {
+			e += 2*y + 1
+		} else {
+			x--
+			e += 2*(y-x) + 1
+		}
+	}
+}
+
+// Blit copies all dots of another frame onto this one with its top left corner at x,y
+func (f *Frame) Blit(src *Frame, x int, y int) {
+	for row := range src.height {
+		for col := range src.width {
+			f.SetPixel(x+col, y+row, src.GetPixel(col, row))
+		}
+	}
+}
+
+// Invert flips every dot of the frame
+func (f *Frame) Invert() {
+	for i := range f.pixels {
+		f.pixels[i] = !f.pixels[i]
+	}
+}
+
+// Clear unsets every dot of the frame
+func (f *Frame) Clear() {
+	for i := range f.pixels {
+		f.pixels[i] = false
+	}
+}
+
+// Columns returns the frame in the column format used by DisplayOutput:
+// one value per column, the lowest bit is the top row
+func (f *Frame) Columns() []uint32 {
+	columns := make([]uint32, f.width)
+	for x := range f.width {
+		for y := range min(f.height, maxDisplayHeight) {
+			if f.pixels[y*f.width+x] {
+				columns[x] |= 1 << y
+			}
+		}
+	}
+	return columns
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
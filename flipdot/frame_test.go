@@ -0,0 +1,145 @@
+package flipdot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// frameString draws a frame as rows of '#' and '.' to make the expected output easy to read
+func frameString(f *Frame) string {
+	var rows []string
+	for y := range f.Height() {
+		row := ""
+		for x := range f.Width() {
+			if f.GetPixel(x, y) {
+				row += "#"
+			} else {
+				row += "."
+			}
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestFrameDrawing(t *testing.T) {
+	testCases := []struct {
+		name     string
+		draw     func(f *Frame)
+		expected []string
+	}{
+		{
+			name: "set pixel with clipping",
+			draw: func(f *Frame) {
+				f.SetPixel(0, 0, true)
+				f.SetPixel(4, 4, true)
+				f.SetPixel(-1, 2, true)
+				f.SetPixel(5, 2, true)
+			},
+			expected: []string{"#....", ".....", ".....", ".....", "....#"},
+		},
+		{
+			name:     "diagonal line",
+			draw:     func(f *Frame) { f.Line(4, 4, 0, 0, true) },
+			expected: []string{"#....", ".#...", "..#..", "...#.", "....#"},
+		},
+		{
+			name:     "shallow line",
+			draw:     func(f *Frame) { f.Line(0, 1, 4, 2, true) },
+			expected: []string{".....", "##...", "..###", ".....", "....."},
+		},
+		{
+			name:     "rect",
+			draw:     func(f *Frame) { f.Rect(1, 1, 4, 3, true) },
+			expected: []string{".....", ".####", ".#..#", ".####", "....."},
+		},
+		{
+			name:     "fill rect",
+			draw:     func(f *Frame) { f.FillRect(3, 3, 5, 5, true) },
+			expected: []string{".....", ".....", ".....", "...##", "...##"},
+		},
+		{
+			name:     "circle",
+			draw:     func(f *Frame) { f.Circle(2, 2, 2, true) },
+			expected: []string{".###.", "#...#", "#...#", "#...#", ".###."},
+		},
+		{
+			name: "invert",
+			draw: func(f *Frame) {
+				f.FillRect(0, 0, 5, 2, true)
+				f.Invert()
+			},
+			expected: []string{".....", ".....", "#####", "#####", "#####"},
+		},
+		{
+			name: "clear",
+			draw: func(f *Frame) {
+				f.FillRect(0, 0, 5, 5, true)
+				f.Clear()
+			},
+			expected: []string{".....", ".....", ".....", ".....", "....."},
+		},
+		{
+			name: "blit",
+			draw: func(f *Frame) {
+				f.FillRect(0, 0, 5, 5, true)
+				src := NewFrame(2, 2)
+				src.SetPixel(0, 0, true)
+				f.Blit(src, 3, 3)
+			},
+			expected: []string{"#####", "#####", "#####", "####.", "###.."},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFrame(5, 5)
+			tc.draw(f)
+			expected := strings.Join(tc.expected, "\n")
+			if actual := frameString(f); actual != expected {
+				t.Errorf("unexpected frame:\nexpected:\n%s\nactual:\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestFrameColumns(t *testing.T) {
+	f := NewFrame(28, 14)
+	f.SetPixel(0, 0, true)
+	f.SetPixel(27, 13, true)
+	f.Line(1, 0, 1, 13, true)
+
+	columns := f.Columns()
+	if len(columns) != 28 {
+		t.Fatalf("expected 28 columns, got %d", len(columns))
+	}
+	if columns[0] != 0b1 || columns[1] != 0b11111111111111 || columns[27] != 1<<13 {
+		t.Errorf("unexpected columns: %b", columns)
+	}
+
+	roundTrip := FrameFromColumns(columns, 14)
+	if !reflect.DeepEqual(roundTrip, f) {
+		t.Errorf("expected frame from columns to match:\n%s\ngot:\n%s", frameString(f), frameString(roundTrip))
+	}
+}
+
+func TestDisplayShowFrame(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout()}
+
+	f := display.NewFrame()
+	f.FillRect(0, 0, 28, 14, true)
+	if err := display.ShowFrame(f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, column := range mock.ShowCalls[0].DisplayData {
+		if column != 0b11111111111111 {
+			t.Errorf("expected column %d to be full, got %b", i, column)
+		}
+	}
+
+	if err := display.ShowFrame(NewFrame(10, 10)); err == nil {
+		t.Fatal("expected error for a frame that is not the size of the display")
+	}
+}
@@ -0,0 +1,204 @@
+package flipdot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxDisplayHeight is the number of rows that fit in a single uint32 column
+const maxDisplayHeight = 32
+
+// Panel describes a single flipdot module and where it sits on the sign
+type Panel struct {
+	// Address needs to match the value set on the module's controller board DIP switch #1 positions 0-5
+	Address byte
+	// X and Y are the position of the top left dot of the panel on the sign, after rotation
+	X int
+	Y int
+	// Width and Height are the size of the module as it is addressed, e.g. 28x7
+	Width  int
+	Height int
+	// Rotation is the clockwise rotation of the module in degrees, one of 0, 90, 180 or 270
+	Rotation int
+}
+
+// Layout describes how the panels are arranged to make up the whole sign
+type Layout struct {
+	Panels []Panel
+}
+
+// DefaultLayout returns the layout of the Alfa-Zeta XY5 14*28 display: two 7x28 panels stacked on top of each other
+func DefaultLayout() Layout {
+	return GridLayout([][]byte{{0x01}, {0x02}}, 28, 7, 0)
+}
+
+// GridLayout creates a layout of equally sized panels arranged in a grid.
+// Each inner slice is one row of panel addresses from left to right, the same as the
+// flippydot Panel([[1], [2]], 28, 7) layout.
+func GridLayout(addresses [][]byte, panelWidth int, panelHeight int, rotation int) Layout {
+	var rows [][]Panel
+	for _, rowAddresses := range addresses {
+		var row []Panel
+		for _, address := range rowAddresses {
+			row = append(row, Panel{Address: address, Width: panelWidth, Height: panelHeight, Rotation: rotation})
+		}
+		rows = append(rows, row)
+	}
+	return arrangeRows(rows)
+}
+
+// ParseLayout creates a grid layout from a string of panel addresses.
+// Rows are separated by ';' and the panels in a row by ',', so "1;2" is two panels stacked
+// vertically and "1,2;3,4" is a 2x2 grid. Addresses can be decimal or hex, e.g. "0x01".
+// Every panel is of the given module type unless the address is followed by ':' and
+// another module type, so "1:7x14,2:7x14;3" is two 7x14 modules above a 7x28 module.
+func ParseLayout(spec string, module ModuleType, rotation int) (Layout, error) {
+	var rows [][]Panel
+	for _, rowSpec := range strings.Split(spec, ";") {
+		var row []Panel
+		for _, panelSpec := range strings.Split(rowSpec, ",") {
+			addressSpec, moduleName, hasModule := strings.Cut(strings.TrimSpace(panelSpec), ":")
+			address, err := strconv.ParseUint(addressSpec, 0, 8)
+			if err != nil {
+				return Layout{}, fmt.Errorf("invalid panel address '%s' in layout '%s'", addressSpec, spec)
+			}
+			panelModule := module
+			if hasModule {
+				panelModule, err = ModuleTypeByName(moduleName)
+				if err != nil {
+					return Layout{}, err
+				}
+			}
+			row = append(row, Panel{Address: byte(address), Width: panelModule.Width, Height: panelModule.Height, Rotation: rotation})
+		}
+		rows = append(rows, row)
+	}
+
+	layout := arrangeRows(rows)
+	if err := layout.Validate(); err != nil {
+		return Layout{}, err
+	}
+	return layout, nil
+}
+
+// arrangeRows positions rows of panels from left to right and top to bottom.
+// Each row is as high as its highest panel.
+func arrangeRows(rows [][]Panel) Layout {
+	layout := Layout{}
+	y := 0
+	for _, row := range rows {
+		x := 0
+		rowHeight := 0
+		for _, p := range row {
+			p.X = x
+			p.Y = y
+			layout.Panels = append(layout.Panels, p)
+			w, h := p.size()
+			x += w
+			rowHeight = max(rowHeight, h)
+		}
+		y += rowHeight
+	}
+	return layout
+}
+
+// Width returns the width of the whole sign in dots
+func (l Layout) Width() int {
+	width := 0
+	for _, p := range l.Panels {
+		w, _ := p.size()
+		width = max(width, p.X+w)
+	}
+	return width
+}
+
+// Height returns the height of the whole sign in dots
+func (l Layout) Height() int {
+	height := 0
+	for _, p := range l.Panels {
+		_, h := p.size()
+		height = max(height, p.Y+h)
+	}
+	return height
+}
+
+// Validate checks that the panels make up a sign that can be displayed
+func (l Layout) Validate() error {
+	if len(l.Panels) == 0 {
+		return fmt.Errorf("layout has no panels")
+	}
+
+	addresses := map[byte]bool{}
+	for i, p := range l.Panels {
+		if p.Width < 1 || p.Height < 1 || p.Height > 7 {
+			return fmt.Errorf("panel %d has invalid size %dx%d, height must be between 1 and 7", i, p.Width, p.Height)
+		}
+		if p.Rotation != 0 && p.Rotation != 90 && p.Rotation != 180 && p.Rotation != 270 {
+			return fmt.Errorf("panel %d has invalid rotation %d, must be one of 0, 90, 180 or 270", i, p.Rotation)
+		}
+		if p.X < 0 || p.Y < 0 {
+			return fmt.Errorf("panel %d has negative position %d,%d", i, p.X, p.Y)
+		}
+		if addresses[p.Address] {
+			return fmt.Errorf("panel %d uses address 0x%02x which is already used by another panel", i, p.Address)
+		}
+		addresses[p.Address] = true
+
+		for j, other := range l.Panels[:i] {
+			if p.overlaps(other) {
+				return fmt.Errorf("panel %d overlaps panel %d", i, j)
+			}
+		}
+	}
+
+	if l.Height() > maxDisplayHeight {
+		return fmt.Errorf("layout is %d dots high, the maximum is %d", l.Height(), maxDisplayHeight)
+	}
+
+	return nil
+}
+
+// panelData returns the dots of one panel in the format the module expects:
+// one byte per column, LSB is the upper dot
+func (l Layout) panelData(displayData []uint32, panel int) []byte {
+	p := l.Panels[panel]
+	data := make([]byte, p.Width)
+	for col := range p.Width {
+		for row := range p.Height {
+			x, y := p.position(col, row)
+			if x < len(displayData) && displayData[x]&(1<<y) != 0 {
+				data[col] |= 1 << row
+			}
+		}
+	}
+	return data
+}
+
+// size returns the width and height the panel takes up on the sign after rotation
+func (p Panel) size() (int, int) {
+	if p.Rotation == 90 || p.Rotation == 270 {
+		return p.Height, p.Width
+	}
+	return p.Width, p.Height
+}
+
+// position returns the position on the sign of a dot of the panel
+func (p Panel) position(col int, row int) (int, int) {
+	switch p.Rotation {
+	case 90:
+		return p.X + p.Height - 1 - row, p.Y + col
+	case 180:
+		return p.X + p.Width - 1 - col, p.Y + p.Height - 1 - row
+	case 270:
+		return p.X + row, p.Y + p.Width - 1 - col
+	default:
+		return p.X + col, p.Y + row
+	}
+}
+
+func (p Panel) overlaps(other Panel) bool {
+	w, h := p.size()
+	ow, oh := other.size()
+	return p.X < other.X+ow && other.X < p.X+w && p.Y < other.Y+oh && other.Y < p.Y+h
+}
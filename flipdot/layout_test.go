@@ -0,0 +1,169 @@
+package flipdot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLayout(t *testing.T) {
+	testCases := []struct {
+		name           string
+		spec           string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "default 28x14", spec: "1;2", expectedWidth: 28, expectedHeight: 14},
+		{name: "56x14", spec: "1,2;3,4", expectedWidth: 56, expectedHeight: 14},
+		{name: "28x28", spec: "1;2;3;4", expectedWidth: 28, expectedHeight: 28},
+		{name: "hex addresses", spec: "0x01;0x02", expectedWidth: 28, expectedHeight: 14},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layout, err := ParseLayout(tc.spec, Module7x28, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if layout.Width() != tc.expectedWidth || layout.Height() != tc.expectedHeight {
+				t.Errorf("expected %dx%d, got %dx%d", tc.expectedWidth, tc.expectedHeight, layout.Width(), layout.Height())
+			}
+		})
+	}
+
+	t.Run("matches default layout", func(t *testing.T) {
+		layout, err := ParseLayout("1;2", Module7x28, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(layout, DefaultLayout()) {
+			t.Errorf("expected %v, got %v", DefaultLayout(), layout)
+		}
+	})
+
+	t.Run("rotated panels", func(t *testing.T) {
+		layout, err := ParseLayout("1,2,3,4", Module7x28, 90)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if layout.Width() != 28 || layout.Height() != 28 {
+			t.Errorf("expected 28x28, got %dx%d", layout.Width(), layout.Height())
+		}
+	})
+
+	invalid := []struct {
+		name string
+		spec string
+		rot  int
+	}{
+		{name: "empty", spec: "", rot: 0},
+		{name: "not a number", spec: "1;x", rot: 0},
+		{name: "address too large", spec: "1;256", rot: 0},
+		{name: "duplicate address", spec: "1;1", rot: 0},
+		{name: "invalid rotation", spec: "1;2", rot: 45},
+		{name: "too high", spec: "1;2;3;4;5", rot: 0},
+	}
+
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseLayout(tc.spec, Module7x28, tc.rot)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestLayoutValidateOverlap(t *testing.T) {
+	layout := Layout{Panels: []Panel{
+		{Address: 1, X: 0, Y: 0, Width: 28, Height: 7},
+		{Address: 2, X: 10, Y: 3, Width: 28, Height: 7},
+	}}
+	if err := layout.Validate(); err == nil {
+		t.Fatal("expected error for overlapping panels")
+	}
+}
+
+func TestLayoutPanelData(t *testing.T) {
+	// a single dot in the top left corner of the sign
+	displayData := make([]uint32, 28)
+	displayData[0] = 0b1
+
+	testCases := []struct {
+		name     string
+		rotation int
+		col      int
+		bit      byte
+	}{
+		{name: "no rotation", rotation: 0, col: 0, bit: 0b1},
+		{name: "rotated 180", rotation: 180, col: 27, bit: 0b1000000},
+		{name: "rotated 90", rotation: 90, col: 0, bit: 0b1000000},
+		{name: "rotated 270", rotation: 270, col: 27, bit: 0b1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layout := GridLayout([][]byte{{1}}, 28, 7, tc.rotation)
+			data := layout.panelData(displayData, 0)
+			expected := make([]byte, 28)
+			expected[tc.col] = tc.bit
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("expected %v, got %v", expected, data)
+			}
+		})
+	}
+}
+
+func TestPrepareSerialFramesWideLayout(t *testing.T) {
+	layout, err := ParseLayout("1,2;3,4", Module7x28, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &SerialOutput{layout: layout}
+
+	// one dot in the bottom right corner of the sign
+	input := make([]uint32, 56)
+	input[55] = 1 << 13
+
+	frames, err := s.prepareSerialFrames(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 4 {
+		t.Fatalf("expected 4 frames, got %d", len(frames))
+	}
+	if frames[3][27] != 0b1000000 {
+		t.Errorf("expected dot in the last column of panel 4, got %v", frames[3])
+	}
+
+	_, err = s.prepareSerialFrames(make([]uint32, 28))
+	if err == nil {
+		t.Fatal("expected error for display data narrower than the layout")
+	}
+}
+
+func TestDisplayAdaptsToLayout(t *testing.T) {
+	layout, err := ParseLayout("1;2;3;4", Module7x28, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: layout}
+
+	if err := display.ShowTime(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := mock.ShowCalls[0].DisplayData
+	if len(frame) != 28 {
+		t.Fatalf("expected 28 columns, got %d", len(frame))
+	}
+	// the 14 row font is centered vertically, so the top 7 rows stay blank
+	for i, column := range frame {
+		if column&0b1111111 != 0 {
+			t.Errorf("expected top rows of column %d to be blank, got %b", i, column)
+		}
+	}
+
+	if err := display.Show(make([]uint32, 56)); err == nil {
+		t.Fatal("expected error for display data wider than the display")
+	}
+}
@@ -0,0 +1,54 @@
+package flipdot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModuleType describes one of the Alfa-Zeta XY5 module sizes and the commands used to send data to it
+type ModuleType struct {
+	Name   string
+	Width  int
+	Height int
+	// RefreshCommand shows the data on the module as soon as it is received
+	RefreshCommand byte
+	// BufferedCommand stores the data until a flush is received, zero if the module does not support it
+	BufferedCommand byte
+}
+
+// from the protocol document: the command decides the number of data bytes, one per column of 7 dots
+var (
+	Module7x7  = ModuleType{Name: "7x7", Width: 7, Height: 7, RefreshCommand: 0x87}
+	Module7x14 = ModuleType{Name: "7x14", Width: 14, Height: 7, RefreshCommand: 0x92, BufferedCommand: 0x93}
+	Module7x28 = ModuleType{Name: "7x28", Width: 28, Height: 7, RefreshCommand: 0x83, BufferedCommand: 0x84}
+
+	moduleTypes = []ModuleType{Module7x7, Module7x14, Module7x28}
+)
+
+// ModuleTypeByName returns the module type with a name like "7x28"
+func ModuleTypeByName(name string) (ModuleType, error) {
+	for _, m := range moduleTypes {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return ModuleType{}, fmt.Errorf("module type '%s' not supported, must be one of %s", name, strings.Join(moduleTypeNames(), ", "))
+}
+
+// moduleForPanel returns the module type matching the size of a panel
+func moduleForPanel(p Panel) (ModuleType, error) {
+	for _, m := range moduleTypes {
+		if m.Width == p.Width && m.Height == p.Height {
+			return m, nil
+		}
+	}
+	return ModuleType{}, fmt.Errorf("panel with address 0x%02x is %dx%d, which does not match any module type (%s)", p.Address, p.Width, p.Height, strings.Join(moduleTypeNames(), ", "))
+}
+
+func moduleTypeNames() []string {
+	var names []string
+	for _, m := range moduleTypes {
+		names = append(names, m.Name)
+	}
+	return names
+}
@@ -0,0 +1,109 @@
+package flipdot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrorPolicy decides what a MultiOutput does when one of its outputs fails
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyFail returns the error, which stops what is being shown
+	ErrorPolicyFail ErrorPolicy = iota
+	// ErrorPolicyLog logs the error and keeps sending frames to the output
+	ErrorPolicyLog
+	// ErrorPolicyDisable logs the error and stops sending frames to the output
+	ErrorPolicyDisable
+)
+
+var errorPolicyNames = map[string]ErrorPolicy{
+	"fail":    ErrorPolicyFail,
+	"log":     ErrorPolicyLog,
+	"disable": ErrorPolicyDisable,
+}
+
+// ParseErrorPolicy returns the error policy with a name of "fail", "log" or "disable"
+func ParseErrorPolicy(name string) (ErrorPolicy, error) {
+	policy, ok := errorPolicyNames[name]
+	if !ok {
+		return 0, fmt.Errorf("error policy '%s' not supported, must be one of 'fail', 'log' or 'disable'", name)
+	}
+	return policy, nil
+}
+
+// MultiOutput implements DisplayOutput by sending every frame to several outputs
+type MultiOutput struct {
+	outputs []*multiOutputEntry
+}
+
+type multiOutputEntry struct {
+	name     string
+	output   DisplayOutput
+	policy   ErrorPolicy
+	disabled bool
+}
+
+// NewMultiOutput creates a MultiOutput without any outputs
+func NewMultiOutput() *MultiOutput {
+	return &MultiOutput{}
+}
+
+// Add adds an output, name is used in errors and log messages
+func (m *MultiOutput) Add(name string, output DisplayOutput, policy ErrorPolicy) {
+	m.outputs = append(m.outputs, &multiOutputEntry{name: name, output: output, policy: policy})
+}
+
+// Show method for MultiOutput
+// Every output is sent the frame, even if an earlier one fails
+func (m *MultiOutput) Show(displayData []uint32) error {
+	var errs []error
+	for _, o := range m.outputs {
+		if o.disabled {
+			continue
+		}
+
+		err := o.output.Show(displayData)
+		if err == nil {
+			continue
+		}
+
+		switch o.policy {
+		case ErrorPolicyLog:
+			log.Errorf("Output %s failed: %v", o.name, err)
+		case ErrorPolicyDisable:
+			log.Errorf("Output %s failed, disabling it: %v", o.name, err)
+			o.disabled = true
+		default:
+			errs = append(errs, fmt.Errorf("output %s: %v", o.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close method for MultiOutput
+func (m *MultiOutput) Close() error {
+	var errs []error
+	for _, o := range m.outputs {
+		if err := o.output.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("output %s: %v", o.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// errorPolicySeparator separates an output from its error policy, e.g. "web://:8081?on-error=disable"
+const errorPolicySeparator = "?on-error="
+
+// splitErrorPolicy splits an output into the output and its error policy
+func splitErrorPolicy(spec string) (string, ErrorPolicy, error) {
+	output, policyName, found := strings.Cut(spec, errorPolicySeparator)
+	if !found {
+		return spec, ErrorPolicyFail, nil
+	}
+	policy, err := ParseErrorPolicy(policyName)
+	return output, policy, err
+}
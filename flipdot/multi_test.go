@@ -0,0 +1,108 @@
+package flipdot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiOutput(t *testing.T) {
+	failure := errors.New("port disconnected")
+
+	testCases := []struct {
+		name          string
+		policy        ErrorPolicy
+		expectError   bool
+		expectedCalls int
+	}{
+		{name: "fail", policy: ErrorPolicyFail, expectError: true, expectedCalls: 2},
+		{name: "log", policy: ErrorPolicyLog, expectError: false, expectedCalls: 2},
+		{name: "disable", policy: ErrorPolicyDisable, expectError: false, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failing := &MockDisplayOutput{ShowError: failure}
+			working := &MockDisplayOutput{}
+			multi := NewMultiOutput()
+			multi.Add("failing", failing, tc.policy)
+			multi.Add("working", working, ErrorPolicyFail)
+
+			for range 2 {
+				err := multi.Show(make([]uint32, 28))
+				if tc.expectError != (err != nil) {
+					t.Fatalf("expected error %v, got %v", tc.expectError, err)
+				}
+				if err != nil && err.Error() != "output failing: port disconnected" {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+
+			if len(failing.ShowCalls) != tc.expectedCalls {
+				t.Errorf("expected %d calls to the failing output, got %d", tc.expectedCalls, len(failing.ShowCalls))
+			}
+			// the other output always gets every frame
+			if len(working.ShowCalls) != 2 {
+				t.Errorf("expected 2 calls to the working output, got %d", len(working.ShowCalls))
+			}
+
+			if err := multi.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !failing.Closed || !working.Closed {
+				t.Error("expected all outputs to be closed")
+			}
+		})
+	}
+}
+
+func TestNewDisplayOutputs(t *testing.T) {
+	t.Run("single output is not wrapped", func(t *testing.T) {
+		display, err := NewDisplay(Config{Outputs: []string{"terminal"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := display.output.(*TerminalOutput); !ok {
+			t.Errorf("expected TerminalOutput, got %T", display.output)
+		}
+	})
+
+	t.Run("several outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		display, err := NewDisplay(Config{
+			Terminal: true,
+			Outputs:  []string{"png://" + dir, "web://127.0.0.1:0?on-error=disable"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer display.Close()
+
+		multi, ok := display.output.(*MultiOutput)
+		if !ok {
+			t.Fatalf("expected MultiOutput, got %T", display.output)
+		}
+		expected := []struct {
+			name   string
+			policy ErrorPolicy
+		}{
+			{name: "png://" + dir, policy: ErrorPolicyFail},
+			{name: "web://127.0.0.1:0", policy: ErrorPolicyDisable},
+			{name: "terminal", policy: ErrorPolicyFail},
+		}
+		if len(multi.outputs) != len(expected) {
+			t.Fatalf("expected %d outputs, got %d", len(expected), len(multi.outputs))
+		}
+		for i, e := range expected {
+			if multi.outputs[i].name != e.name || multi.outputs[i].policy != e.policy {
+				t.Errorf("output %d: expected %s with policy %d, got %s with policy %d", i, e.name, e.policy, multi.outputs[i].name, multi.outputs[i].policy)
+			}
+		}
+	})
+
+	t.Run("invalid error policy", func(t *testing.T) {
+		_, err := NewDisplay(Config{Outputs: []string{"terminal", "web://127.0.0.1:0?on-error=ignore"}})
+		if err == nil {
+			t.Fatal("expected error for invalid error policy")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package flipdot
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// from the protocol document: the LAN data frame has this many bytes for the pix command, pix data length and data
+const lanPayloadLength = 255
+
+// UDPOutput implements DisplayOutput for panels with a LAN controller
+// Each panel has its own IP address and port, set with the Alfa-Zeta configuration software
+type UDPOutput struct {
+	conns     []net.Conn
+	layout    Layout
+	requestID uint32
+}
+
+// NewUDPOutput creates a UDPOutput sending to one "host:port" endpoint per panel, in the order of the layout.
+// If there is a single endpoint and several panels, the panels use consecutive ports starting at the given one.
+func NewUDPOutput(endpoints []string, layout Layout) (*UDPOutput, error) {
+	addresses, err := panelEndpoints(endpoints, len(layout.Panels))
+	if err != nil {
+		return nil, err
+	}
+
+	u := &UDPOutput{layout: layout}
+	for _, address := range addresses {
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			u.Close()
+			return nil, fmt.Errorf("failed to connect to %s: %v", address, err)
+		}
+		u.conns = append(u.conns, conn)
+	}
+
+	return u, nil
+}
+
+func (u *UDPOutput) Show(displayData []uint32) error {
+	if len(displayData) != u.layout.Width() {
+		return fmt.Errorf("display data has %d columns, layout is %d columns wide", len(displayData), u.layout.Width())
+	}
+
+	for i, p := range u.layout.Panels {
+		module, err := moduleForPanel(p)
+		if err != nil {
+			return err
+		}
+
+		u.requestID++
+		frame := lanFrame(u.requestID, module.RefreshCommand, u.layout.panelData(displayData, i))
+		_, err = u.conns[i].Write(frame)
+		if err != nil {
+			return fmt.Errorf("failed to send to panel 0x%02x: %v", p.Address, err)
+		}
+	}
+
+	return nil
+}
+
+// Close method for UDPOutput
+func (u *UDPOutput) Close() error {
+	var firstErr error
+	for _, conn := range u.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// lanFrame builds a LAN data frame as described in the protocol document
+func lanFrame(requestID uint32, command byte, data []byte) []byte {
+	var frame []byte
+
+	// from the protocol document: the protocol command is always this value
+	frame = append(frame, 0x66)
+	// from the protocol document: 4 arbitrary bytes that let the controller match responses to requests
+	frame = binary.BigEndian.AppendUint32(frame, requestID)
+	// from the protocol document: the same commands as the serial protocol, e.g. 0x92 to show a 7x14 display immediately
+	frame = append(frame, command)
+	frame = append(frame, byte(len(data)))
+	frame = append(frame, data...)
+	// from the protocol document: the pix command, length and data are filled up to 255 bytes
+	frame = append(frame, make([]byte, lanPayloadLength-2-len(data))...)
+	// from the protocol document: CRC-CCITT (0xFFFF) with the bytes swapped like in Modbus
+	frame = binary.LittleEndian.AppendUint16(frame, crcCCITT(frame))
+
+	return frame
+}
+
+// crcCCITT calculates the CRC-CCITT checksum with polynomial 0x1021 and initial value 0xFFFF
+func crcCCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// panelEndpoints returns one network address per panel
+func panelEndpoints(endpoints []string, panels int) ([]string, error) {
+	if len(endpoints) == panels {
+		return endpoints, nil
+	}
+	if len(endpoints) != 1 {
+		return nil, fmt.Errorf("%d endpoints given for %d panels, give one endpoint per panel or a single endpoint", len(endpoints), panels)
+	}
+
+	host, portSpec, err := net.SplitHostPort(endpoints[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint '%s': %v", endpoints[0], err)
+	}
+	port, err := strconv.Atoi(portSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in endpoint '%s'", endpoints[0])
+	}
+
+	var addresses []string
+	for i := range panels {
+		addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(port+i)))
+	}
+	return addresses, nil
+}
+
+// newTCPOutput creates a SerialOutput that writes the serial frames to a TCP connection,
+// for panels connected through a serial to Ethernet converter
+func newTCPOutput(address string, config Config, layout Layout) (*SerialOutput, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", address, err)
+	}
+	return newSerialOutputWithPort(conn, config, layout), nil
+}
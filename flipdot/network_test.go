@@ -0,0 +1,176 @@
+package flipdot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lanFrameData checks a LAN data frame and returns the pix command and data
+func lanFrameData(t *testing.T, frame []byte) (byte, []byte) {
+	t.Helper()
+
+	if len(frame) != 1+4+lanPayloadLength+2 {
+		t.Fatalf("expected frame of %d bytes, got %d", 1+4+lanPayloadLength+2, len(frame))
+	}
+	if frame[0] != 0x66 {
+		t.Fatalf("expected protocol command 0x66, got 0x%02x", frame[0])
+	}
+	crc := binary.LittleEndian.Uint16(frame[len(frame)-2:])
+	if expected := crcCCITT(frame[:len(frame)-2]); crc != expected {
+		t.Fatalf("expected crc 0x%04x, got 0x%04x", expected, crc)
+	}
+
+	length := int(frame[6])
+	return frame[5], frame[7 : 7+length]
+}
+
+func TestLANFrame(t *testing.T) {
+	// the two examples for a 7x14 panel from the protocol document
+	testCases := []struct {
+		name string
+		data []byte
+		crc  string
+	}{
+		{name: "all dots black", data: make([]byte, 14), crc: "bd5b"},
+		{name: "all dots white", data: bytes.Repeat([]byte{0x7f}, 14), crc: "18b0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			frame := lanFrame(0xe3afdc32, 0x92, tc.data)
+
+			expectedStart := append([]byte{0x66, 0xe3, 0xaf, 0xdc, 0x32, 0x92, 0x0e}, tc.data...)
+			if !bytes.Equal(frame[:len(expectedStart)], expectedStart) {
+				t.Errorf("unexpected frame start: % x", frame[:len(expectedStart)])
+			}
+			if crc := hex.EncodeToString(frame[len(frame)-2:]); crc != tc.crc {
+				t.Errorf("expected crc %s, got %s", tc.crc, crc)
+			}
+			command, data := lanFrameData(t, frame)
+			if command != 0x92 || !bytes.Equal(data, tc.data) {
+				t.Errorf("unexpected command 0x%02x or data % x", command, data)
+			}
+		})
+	}
+}
+
+func TestUDPOutput(t *testing.T) {
+	// one listener per panel of the default layout
+	var listeners []net.PacketConn
+	var endpoints []string
+	for range 2 {
+		l, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer l.Close()
+		listeners = append(listeners, l)
+		endpoints = append(endpoints, l.LocalAddr().String())
+	}
+
+	display, err := NewDisplay(Config{Outputs: []string{"udp://" + strings.Join(endpoints, ",")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer display.Close()
+
+	input := make([]uint32, 28)
+	input[0] = 0b1      // top left dot of the top panel
+	input[27] = 1 << 13 // bottom right dot of the bottom panel
+	err = display.Show(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]byte{make([]byte, 28), make([]byte, 28)}
+	expected[0][0] = 0b1
+	expected[1][27] = 0b1000000
+
+	for i, l := range listeners {
+		buf := make([]byte, 1024)
+		l.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := l.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("panel %d: failed to read frame: %v", i, err)
+		}
+
+		command, data := lanFrameData(t, buf[:n])
+		if command != 0x83 {
+			t.Errorf("panel %d: expected command 0x83, got 0x%02x", i, command)
+		}
+		if !bytes.Equal(data, expected[i]) {
+			t.Errorf("panel %d: expected data % x, got % x", i, expected[i], data)
+		}
+	}
+}
+
+func TestPanelEndpoints(t *testing.T) {
+	addresses, err := panelEndpoints([]string{"10.0.0.5:5000"}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"10.0.0.5:5000", "10.0.0.5:5001", "10.0.0.5:5002"}
+	if strings.Join(addresses, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected %v, got %v", expected, addresses)
+	}
+
+	_, err = panelEndpoints([]string{"10.0.0.5:5000", "10.0.0.6:5000"}, 3)
+	if err == nil {
+		t.Fatal("expected error when the number of endpoints does not match the panels")
+	}
+
+	_, err = panelEndpoints([]string{"10.0.0.5"}, 2)
+	if err == nil {
+		t.Fatal("expected error for an endpoint without a port")
+	}
+}
+
+func TestTCPOutput(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	received := make(chan []byte)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	display, err := NewDisplay(Config{Outputs: []string{"tcp://" + l.Addr().String()}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = display.Show(make([]uint32, 28))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	display.Close()
+
+	// the same frames as the serial output
+	expected := append(serialFrame(0x83, 0x01, make([]byte, 28)), serialFrame(0x83, 0x02, make([]byte, 28))...)
+	if data := <-received; !bytes.Equal(data, expected) {
+		t.Errorf("unexpected bytes received:\nexpected: % x\nactual:   % x", expected, data)
+	}
+}
+
+func TestNewDisplayInvalidOutput(t *testing.T) {
+	_, err := NewDisplay(Config{Outputs: []string{"carrier-pigeon://"}})
+	if err == nil {
+		t.Fatal("expected error for unsupported output")
+	}
+}
@@ -0,0 +1,167 @@
+package flipdot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// recordingDotSize is the size in pixels of each dot in recorded images
+	recordingDotSize = 10
+	// lastFrameDelay is how long the last frame of a GIF is shown before it loops
+	lastFrameDelay = 2 * time.Second
+	// minFrameDelay is the shortest delay browsers show, shorter GIF delays are slowed down by them
+	minFrameDelay = 20 * time.Millisecond
+	// maxGIFFrames is the most frames a GIF records, as they are kept in memory until the GIF is written
+	maxGIFFrames = 3000
+)
+
+// recordingPalette has the background, a dot that is off and a dot that is on
+var recordingPalette = color.Palette{
+	color.RGBA{0x11, 0x11, 0x11, 0xff},
+	color.RGBA{0x2a, 0x2a, 0x2a, 0xff},
+	color.RGBA{0xf4, 0xf0, 0xd0, 0xff},
+}
+
+// GIFOutput implements DisplayOutput by recording every frame into an animated GIF
+// Each frame is shown for as long as it was on the display. The file is written when the output is closed,
+// or when maxFrames have been recorded, after which the frames are no longer recorded.
+type GIFOutput struct {
+	path      string
+	height    int
+	frames    [][]uint32
+	times     []time.Time
+	now       func() time.Time
+	maxFrames int
+	written   bool
+}
+
+// PNGOutput implements DisplayOutput by writing every frame to a numbered PNG file in a directory
+type PNGOutput struct {
+	dir    string
+	height int
+	count  int
+}
+
+// NewGIFOutput creates a GIFOutput that writes to path when closed
+func NewGIFOutput(path string, layout Layout) *GIFOutput {
+	return &GIFOutput{path: path, height: layout.Height(), now: time.Now, maxFrames: maxGIFFrames}
+}
+
+// NewPNGOutput creates a PNGOutput writing frame-00001.png, frame-00002.png... into dir
+func NewPNGOutput(dir string, layout Layout) (*PNGOutput, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %v", dir, err)
+	}
+	return &PNGOutput{dir: dir, height: layout.Height()}, nil
+}
+
+// Show method for GIFOutput
+func (g *GIFOutput) Show(displayData []uint32) error {
+	if g.written {
+		return nil
+	}
+	g.frames = append(g.frames, append([]uint32(nil), displayData...))
+	g.times = append(g.times, g.now())
+	if len(g.frames) >= g.maxFrames {
+		log.Warnf("Recorded %d frames, the most a GIF records, writing %s and recording no more", len(g.frames), g.path)
+		return g.write()
+	}
+	return nil
+}
+
+// Close method for GIFOutput - writes the GIF unless it has already been written
+func (g *GIFOutput) Close() error {
+	if g.written || len(g.frames) == 0 {
+		return nil
+	}
+	return g.write()
+}
+
+// write encodes the recorded frames into the GIF file and frees them
+func (g *GIFOutput) write() error {
+	g.written = true
+	animation := &gif.GIF{}
+	for i, frame := range g.frames {
+		animation.Image = append(animation.Image, renderImage(frame, g.height))
+		delay := lastFrameDelay
+		if i+1 < len(g.times) {
+			delay = max(g.times[i+1].Sub(g.times[i]), minFrameDelay)
+		}
+		// GIF delays are in 100ths of a second
+		animation.Delay = append(animation.Delay, int(delay.Round(10*time.Millisecond)/(10*time.Millisecond)))
+	}
+	g.frames, g.times = nil, nil
+
+	f, err := os.Create(g.path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", g.path, err)
+	}
+	defer f.Close()
+
+	err = gif.EncodeAll(f, animation)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", g.path, err)
+	}
+	return f.Close()
+}
+
+// Show method for PNGOutput
+func (p *PNGOutput) Show(displayData []uint32) error {
+	p.count++
+	path := filepath.Join(p.dir, fmt.Sprintf("frame-%05d.png", p.count))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	err = png.Encode(f, renderImage(displayData, p.height))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// Close method for PNGOutput
+func (p *PNGOutput) Close() error {
+	return nil // Every frame has already been written
+}
+
+// renderImage draws the display data as round dots like the real XY5 panels
+func renderImage(displayData []uint32, height int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, len(displayData)*recordingDotSize, height*recordingDotSize), recordingPalette)
+
+	// the dot is a circle slightly smaller than its square, leaving a gap to the next dot
+	center := float64(recordingDotSize-1) / 2
+	radius := float64(recordingDotSize) * 0.42
+
+	for col, column := range displayData {
+		for row := range height {
+			c := uint8(1)
+			if column&(1<<row) != 0 {
+				c = 2
+			}
+			for py := range recordingDotSize {
+				for px := range recordingDotSize {
+					dx := float64(px) - center
+					dy := float64(py) - center
+					if dx*dx+dy*dy <= radius*radius {
+						img.SetColorIndex(col*recordingDotSize+px, row*recordingDotSize+py, c)
+					}
+				}
+			}
+		}
+	}
+
+	return img
+}
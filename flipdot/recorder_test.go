@@ -0,0 +1,120 @@
+package flipdot
+
+import (
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGIFOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	g := NewGIFOutput(path, DefaultLayout())
+
+	// frames shown 100ms, 250ms and 5ms apart
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	times := []time.Time{start, start.Add(100 * time.Millisecond), start.Add(350 * time.Millisecond), start.Add(355 * time.Millisecond)}
+	for i, now := range times {
+		g.now = func() time.Time { return now }
+		displayData := make([]uint32, 28)
+		displayData[i] = 0b1
+		if err := g.Show(displayData); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := g.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	animation, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("failed to decode gif: %v", err)
+	}
+
+	if len(animation.Image) != 4 {
+		t.Fatalf("expected 4 frames, got %d", len(animation.Image))
+	}
+	// the 5ms frame is slowed down to the minimum delay and the last frame is held
+	expectedDelays := []int{10, 25, 2, 200}
+	if !reflect.DeepEqual(animation.Delay, expectedDelays) {
+		t.Errorf("expected delays %v, got %v", expectedDelays, animation.Delay)
+	}
+
+	bounds := animation.Image[0].Bounds()
+	if bounds.Dx() != 28*recordingDotSize || bounds.Dy() != 14*recordingDotSize {
+		t.Errorf("unexpected image size %v", bounds)
+	}
+	// the middle of the first dot is on in the first frame and off in the second
+	middle := recordingDotSize / 2
+	if animation.Image[0].ColorIndexAt(middle, middle) != 2 || animation.Image[1].ColorIndexAt(middle, middle) != 1 {
+		t.Error("expected the first dot to be on only in the first frame")
+	}
+}
+
+// Test that the GIF is written when it has recorded the most frames and later frames are not recorded
+func TestGIFOutputMaxFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	g := NewGIFOutput(path, DefaultLayout())
+	g.maxFrames = 2
+
+	for range 3 {
+		if err := g.Show(make([]uint32, 28)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("expected the GIF to be written before it is closed: %v", err)
+	}
+	defer f.Close()
+	animation, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("failed to decode gif: %v", err)
+	}
+	if len(animation.Image) != 2 {
+		t.Errorf("expected 2 frames, got %d", len(animation.Image))
+	}
+	if err := g.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPNGOutput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "frames")
+	p, err := NewPNGOutput(dir, DefaultLayout())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 3 {
+		if err := p.Show(make([]uint32, 28)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"frame-00001.png", "frame-00002.png", "frame-00003.png"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", name, err)
+		}
+		if img.Bounds().Dx() != 28*recordingDotSize || img.Bounds().Dy() != 14*recordingDotSize {
+			t.Errorf("unexpected image size %v", img.Bounds())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package flipdot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Schedule is the times the clock is shown, the display is blank the rest of the time.
+// An empty schedule shows the clock all the time.
+type Schedule []ScheduleWindow
+
+// ScheduleWindow is a time of day when the clock is shown, e.g. 07:00 to 23:00
+type ScheduleWindow struct {
+	// Days the window starts on, every day if empty
+	Days []time.Weekday
+	// Start and End are the time since midnight. If End is before Start the window ends the next day.
+	Start time.Duration
+	End   time.Duration
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekday returns the weekday for a name like "mon" or "Monday"
+func ParseWeekday(name string) (time.Weekday, error) {
+	lower := strings.ToLower(name)
+	if len(lower) >= 3 {
+		if day, ok := weekdayNames[lower[:3]]; ok && strings.HasPrefix(strings.ToLower(day.String()), lower) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day '%s', must be a name like 'mon' or 'monday'", name)
+}
+
+// ParseTimeOfDay returns the time since midnight of a time like "07:30" or "24:00"
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	var hours, minutes int
+	_, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes)
+	if err != nil || len(value) != 5 || hours < 0 || minutes < 0 || minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time '%s', must be between '00:00' and '24:00'", value)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// Active returns true if the clock should be shown at t
+func (s Schedule) Active(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, w := range s {
+		if w.active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w ScheduleWindow) active(t time.Time) bool {
+	// the time on the clock, as days when daylight saving time starts or ends have 23 or 25 hours
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+
+	if w.Start < w.End {
+		return w.onDay(t.Weekday()) && sinceMidnight >= w.Start && sinceMidnight < w.End
+	}
+	// the window goes past midnight, so the early hours belong to the window that started the day before
+	yesterday := (t.Weekday() + 6) % 7
+	return (w.onDay(t.Weekday()) && sinceMidnight >= w.Start) || (w.onDay(yesterday) && sinceMidnight < w.End)
+}
+
+func (w ScheduleWindow) onDay(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, day)
+}
@@ -0,0 +1,93 @@
+package flipdot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleActive(t *testing.T) {
+	day := ScheduleWindow{Start: 7 * time.Hour, End: 23 * time.Hour}
+	// 22:00 on Friday until 02:00 on Saturday
+	fridayNight := ScheduleWindow{Days: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 2 * time.Hour}
+
+	// 2024-01-05 is a Friday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		schedule Schedule
+		time     time.Time
+		expected bool
+	}{
+		{name: "empty schedule", schedule: nil, time: at(5, 3, 0), expected: true},
+		{name: "inside window", schedule: Schedule{day}, time: at(5, 7, 0), expected: true},
+		{name: "before window", schedule: Schedule{day}, time: at(5, 6, 59), expected: false},
+		{name: "end is not included", schedule: Schedule{day}, time: at(5, 23, 0), expected: false},
+		{name: "past midnight on start day", schedule: Schedule{fridayNight}, time: at(5, 23, 30), expected: true},
+		{name: "past midnight on next day", schedule: Schedule{fridayNight}, time: at(6, 1, 30), expected: true},
+		{name: "past midnight after end", schedule: Schedule{fridayNight}, time: at(6, 2, 0), expected: false},
+		{name: "past midnight on other day", schedule: Schedule{fridayNight}, time: at(4, 23, 30), expected: false},
+		{name: "any window", schedule: Schedule{fridayNight, day}, time: at(4, 12, 0), expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.schedule.Active(tc.time); actual != tc.expected {
+				t.Errorf("expected %v at %s, got %v", tc.expected, tc.time.Format("Mon 15:04"), actual)
+			}
+		})
+	}
+}
+
+// Test that the windows are in the time on the clock when daylight saving time starts and ends
+func TestScheduleActiveDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone not available: %v", err)
+	}
+	schedule := Schedule{{Start: 7 * time.Hour, End: 23 * time.Hour}}
+
+	testCases := []struct {
+		time     time.Time
+		expected bool
+	}{
+		// the clocks go forward at 02:00 on 2024-03-31, so 07:00 is 6 hours after midnight
+		{time: time.Date(2024, time.March, 31, 6, 59, 0, 0, berlin), expected: false},
+		{time: time.Date(2024, time.March, 31, 7, 0, 0, 0, berlin), expected: true},
+		// the clocks go back at 03:00 on 2024-10-27, so 22:59 is 23 hours and 59 minutes after midnight
+		{time: time.Date(2024, time.October, 27, 22, 59, 0, 0, berlin), expected: true},
+		{time: time.Date(2024, time.October, 27, 23, 0, 0, 0, berlin), expected: false},
+	}
+	for _, tc := range testCases {
+		if actual := schedule.Active(tc.time); actual != tc.expected {
+			t.Errorf("expected %v at %v, got %v", tc.expected, tc.time, actual)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, name := range []string{"mon", "Monday", "MON"} {
+		if day, err := ParseWeekday(name); err != nil || day != time.Monday {
+			t.Errorf("expected %s to be Monday, got %v, %v", name, day, err)
+		}
+	}
+	for _, name := range []string{"mo", "monday2", "funday"} {
+		if _, err := ParseWeekday(name); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+
+	if d, err := ParseTimeOfDay("07:30"); err != nil || d != 7*time.Hour+30*time.Minute {
+		t.Errorf("expected 7h30m, got %v, %v", d, err)
+	}
+	if d, err := ParseTimeOfDay("24:00"); err != nil || d != 24*time.Hour {
+		t.Errorf("expected 24h, got %v, %v", d, err)
+	}
+	for _, value := range []string{"7:30", "24:01", "12:60", "noon", "-1:00"} {
+		if _, err := ParseTimeOfDay(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
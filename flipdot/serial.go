@@ -1,28 +1,77 @@
 package flipdot
 
 import (
-	"go.bug.st/serial"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
 )
 
-var (
-	// The two 7x28 displays must have DIP switch #1 positions 0-5 set to match the values here
-	addresses = []byte{0x01, 0x02}
-)
+// from the protocol document: shows the stored data on all connected displays at once
+var flushFrame = []byte{0x80, 0x82, 0x8F}
 
 // SerialOutput implements DisplayOutput for serial communication
 type SerialOutput struct {
-	port serial.Port
+	port   io.WriteCloser
+	layout Layout
+	// buffered sends the panels without refreshing them and then flushes them all at once,
+	// so the panels change together instead of one after the other
+	buffered bool
+	// fullRefreshInterval is how often all panels are sent even if they have not changed,
+	// which corrects dots that got stuck. Zero means never.
+	fullRefreshInterval time.Duration
+	// lastFrames is what was last sent to each panel, nil if the panels are in an unknown state
+	lastFrames      [][]byte
+	lastFullRefresh time.Time
+}
+
+// newSerialOutputWithPort creates a SerialOutput writing to an open port
+func newSerialOutputWithPort(port io.WriteCloser, config Config, layout Layout) *SerialOutput {
+	return &SerialOutput{
+		port:                port,
+		layout:              layout,
+		buffered:            config.Buffered,
+		fullRefreshInterval: config.FullRefreshInterval,
+	}
 }
 
 // Write method for SerialOutput
-// For writing the whole 14*28 display
-func (s *SerialOutput) write(frames [2][28]byte) error {
-	for i := range 2 {
-		err := s.writeSingleDisplay(frames[i], addresses[i])
+// For writing the whole display, one frame per panel
+// Panels that have not changed since they were last sent are skipped
+func (s *SerialOutput) write(frames [][]byte) error {
+	fullRefresh := s.lastFrames == nil
+	if s.fullRefreshInterval > 0 && time.Since(s.lastFullRefresh) >= s.fullRefreshInterval {
+		fullRefresh = true
+	}
+
+	lastFrames := s.lastFrames
+	// the state of the panels is unknown until everything is written
+	s.lastFrames = nil
+
+	sent := 0
+	for i, frame := range frames {
+		if !fullRefresh && bytes.Equal(frame, lastFrames[i]) {
+			continue
+		}
+		err := s.writeSingleDisplay(frame, s.layout.Panels[i])
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+
+	if s.buffered && sent > 0 {
+		_, err := s.port.Write(flushFrame)
 		if err != nil {
 			return err
 		}
 	}
+
+	s.lastFrames = frames
+	if fullRefresh {
+		s.lastFullRefresh = time.Now()
+	}
+
 	return nil
 }
 
@@ -31,50 +80,61 @@ func (s *SerialOutput) Close() error {
 	return s.port.Close()
 }
 
-func (s *SerialOutput) prepareSerialFrames(data [28]uint16) ([2][28]byte, error) {
-	var frames [2][28]byte
-	var topDisplayFrame [28]byte
-	var bottomDisplayFrame [28]byte
-
-	for col := range 28 {
-		bottomByte := data[col] >> 7
-		bottomByteShiftedAgain := bottomByte << 7
-		topByte := data[col] &^ bottomByteShiftedAgain
-		topDisplayFrame[col] = byte(topByte)
-		bottomDisplayFrame[col] = byte(bottomByte)
+func (s *SerialOutput) prepareSerialFrames(data []uint32) ([][]byte, error) {
+	if len(data) != s.layout.Width() {
+		return nil, fmt.Errorf("display data has %d columns, layout is %d columns wide", len(data), s.layout.Width())
 	}
 
-	frames[0] = topDisplayFrame
-	frames[1] = bottomDisplayFrame
+	frames := make([][]byte, len(s.layout.Panels))
+	for i := range s.layout.Panels {
+		frames[i] = s.layout.panelData(data, i)
+	}
 
 	return frames, nil
 }
 
 // writeSingleDisplay method for SerialOutput
-// writes all pixels of one of the two connected 7*28 displays
-func (s *SerialOutput) writeSingleDisplay(data [28]byte, address byte) error {
+// writes all pixels of one of the connected displays
+func (s *SerialOutput) writeSingleDisplay(data []byte, panel Panel) error {
+	module, err := moduleForPanel(panel)
+	if err != nil {
+		return err
+	}
+
+	// from the protocol document: the REFRESH commands update the pixels as the data is received
+	command := module.RefreshCommand
+	// 7x7 modules do not support buffering so they are still refreshed straight away
+	if s.buffered && module.BufferedCommand != 0 {
+		command = module.BufferedCommand
+	}
+
+	_, err = s.port.Write(serialFrame(command, panel.Address, data))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// serialFrame builds a frame as described in the protocol document
+func serialFrame(command byte, address byte, data []byte) []byte {
 	var frame []byte
 
 	// from the protocol document: all frames start with this value
 	frame = append(frame, 0x80)
-	// from the protocol document: enables REFRESH mode, the display will update pixels as the data is received
-	frame = append(frame, 0x83)
+	// from the protocol document: decides the number of data bytes and if the display refreshes as they are received
+	frame = append(frame, command)
 	// from the protocol document: needs to match the value set on the display controller board DIP switch #1 positions 0-5
 	frame = append(frame, address)
-	// the 28 columns of the display
-	frame = append(frame, data[:]...)
+	// one byte per column of the display
+	frame = append(frame, data...)
 	// from the protocol document: all frames end with this value
 	frame = append(frame, 0x8F)
 
-	_, err := s.port.Write(frame)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return frame
 }
 
-func (s *SerialOutput) Show(displayData [28]uint16) error {
+func (s *SerialOutput) Show(displayData []uint32) error {
 	frames, err := s.prepareSerialFrames(displayData)
 	if err != nil {
 		return err
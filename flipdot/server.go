@@ -0,0 +1,380 @@
+package flipdot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Server is an HTTP API for changing what a Display shows at runtime
+//
+//	POST /text          {"text": "Hello", "font": "large", "loop": true, "columns_per_second": 11}
+//	                    {"text": "Hello\nWorld", "font": "small", "static": true, "align": "left", "valign": "top", "wrap": true}
+//	                    {"text": "Hello", "effect": "bounce", "columns_per_second": 15, "pause_start": "2s", "pause_end": "2s"}
+//	POST /frame         {"rows": ["#....", ".#...", ...]} one string per row, '#' is a dot that is on
+//	POST /mode/clock
+//	POST /mode/test-pattern
+//	POST /clear
+//	GET  /state
+type Server struct {
+	display *Display
+	mux     *http.ServeMux
+
+	mu      sync.Mutex
+	state   State
+	current *serverRun
+}
+
+// State describes what the display is showing
+type State struct {
+	Mode string `json:"mode"`
+	Text string `json:"text,omitempty"`
+	Font string `json:"font,omitempty"`
+	Loop bool   `json:"loop,omitempty"`
+	// Static is true if the text is shown without scrolling when it fits
+	Static bool   `json:"static,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Substitutions are the characters of the text that are not in the font and what is shown instead
+	Substitutions []string `json:"substitutions,omitempty"`
+	Width         int      `json:"width"`
+	Height        int      `json:"height"`
+}
+
+// TextRequest is the body of POST /text
+type TextRequest struct {
+	Text string `json:"text"`
+	Font string `json:"font"`
+	Loop bool   `json:"loop"`
+	// ColumnsPerSecond is the speed the text moves at, DefaultColumnsPerSecond if not given
+	ColumnsPerSecond float64 `json:"columns_per_second"`
+	// Speed replaces ColumnsPerSecond with a speed between 1 (slow) and 9 (fast) if set, see ScrollSpeed.
+	//
+	// Deprecated: use ColumnsPerSecond.
+	Speed int `json:"speed"`
+	// Static shows the text without scrolling when it fits, aligned with Align and VAlign
+	Static bool   `json:"static"`
+	Align  string `json:"align"`
+	VAlign string `json:"valign"`
+	Wrap   bool   `json:"wrap"`
+	// Effect is how the text moves, e.g. "scroll-up", see ParseTextEffect
+	Effect string `json:"effect"`
+	// PauseStart and PauseEnd are durations like "2s", see TextOptions
+	PauseStart string `json:"pause_start"`
+	PauseEnd   string `json:"pause_end"`
+}
+
+// options returns the TextOptions of the request
+func (req TextRequest) options() (TextOptions, error) {
+	opts := TextOptions{Font: req.Font, Wrap: req.Wrap, Speed: req.ColumnsPerSecond, Loop: req.Loop}
+	if req.Speed != 0 {
+		if req.Speed < 1 || req.Speed > 9 {
+			return opts, fmt.Errorf("invalid speed %d, must be between 1 and 9", req.Speed)
+		}
+		opts.Speed = ScrollSpeed(req.Speed)
+	} else if req.ColumnsPerSecond <= 0 {
+		return opts, fmt.Errorf("invalid columns_per_second %g, must be positive", req.ColumnsPerSecond)
+	}
+	var err error
+	if opts.Align, err = ParseAlign(req.Align); err != nil {
+		return opts, err
+	}
+	if opts.VAlign, err = ParseVAlign(req.VAlign); err != nil {
+		return opts, err
+	}
+	if opts.Effect, err = ParseTextEffect(req.Effect); err != nil {
+		return opts, err
+	}
+	if req.PauseStart != "" {
+		if opts.PauseStart, err = time.ParseDuration(req.PauseStart); err != nil || opts.PauseStart < 0 {
+			return opts, fmt.Errorf("invalid pause_start '%s', e.g. '2s'", req.PauseStart)
+		}
+	}
+	if req.PauseEnd != "" {
+		if opts.PauseEnd, err = time.ParseDuration(req.PauseEnd); err != nil || opts.PauseEnd < 0 {
+			return opts, fmt.Errorf("invalid pause_end '%s', e.g. '2s'", req.PauseEnd)
+		}
+	}
+	return opts, nil
+}
+
+// FrameRequest is the body of POST /frame
+type FrameRequest struct {
+	Rows []string `json:"rows"`
+}
+
+// serverRun is a mode running in the background
+type serverRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewServer creates a Server controlling the display
+func NewServer(display *Display) *Server {
+	s := &Server{
+		display: display,
+		mux:     http.NewServeMux(),
+		state:   State{Mode: "idle", Width: display.Width(), Height: display.Height()},
+	}
+
+	s.mux.HandleFunc("POST /text", s.handleText)
+	s.mux.HandleFunc("POST /frame", s.handleFrame)
+	s.mux.HandleFunc("POST /mode/clock", s.handleClock)
+	s.mux.HandleFunc("POST /mode/test-pattern", s.handleTestPattern)
+	s.mux.HandleFunc("POST /clear", s.handleClear)
+	s.mux.HandleFunc("GET /state", s.handleState)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// State returns what the display is showing
+func (s *Server) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// StartText stops the running mode and starts scrolling text
+func (s *Server) StartText(text string, scrollSpeed time.Duration, loop bool, fontSize string) error {
+	return s.StartTextEffect(text, TextOptions{Font: fontSize, Speed: intervalSpeed(scrollSpeed), Loop: loop})
+}
+
+// StartTextEffect stops the running mode and starts showing text with an effect, see Display.ShowTextEffect
+func (s *Server) StartTextEffect(text string, opts TextOptions) error {
+	// check the text can be shown before stopping what is on the display
+	substitutions, err := s.display.CheckText(strings.ReplaceAll(text, "\n", " "), opts.Font)
+	if err != nil {
+		return err
+	}
+
+	state := State{Mode: "text", Text: text, Font: opts.Font, Loop: opts.Loop}
+	for _, substitution := range substitutions {
+		state.Substitutions = append(state.Substitutions, substitution.String())
+	}
+	s.start(state, func(ctx context.Context) error {
+		return s.display.ShowTextEffect(ctx, text, opts)
+	})
+	return nil
+}
+
+// StartStaticText stops the running mode and shows text without scrolling, see Display.ShowStaticText
+func (s *Server) StartStaticText(text string, opts TextOptions) error {
+	substitutions, err := s.display.CheckText(strings.ReplaceAll(text, "\n", " "), opts.Font)
+	if err != nil {
+		return err
+	}
+
+	state := State{Mode: "text", Text: text, Font: opts.Font, Loop: opts.Loop, Static: true}
+	for _, substitution := range substitutions {
+		state.Substitutions = append(state.Substitutions, substitution.String())
+	}
+	s.start(state, func(ctx context.Context) error {
+		return s.display.ShowStaticText(ctx, text, opts)
+	})
+	return nil
+}
+
+// StartClock stops the running mode and starts the clock
+func (s *Server) StartClock() {
+	s.start(State{Mode: "clock"}, s.display.RunClock)
+}
+
+// StartTestPattern stops the running mode and starts the test pattern
+func (s *Server) StartTestPattern() {
+	s.start(State{Mode: "test-pattern"}, s.display.RunTestPatternContext)
+}
+
+// ShowFrame stops the running mode and shows a frame
+func (s *Server) ShowFrame(frame *Frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stop()
+	s.setState(State{Mode: "frame"})
+	err := s.display.ShowFrame(frame)
+	if err != nil {
+		s.state.Error = err.Error()
+	}
+	return err
+}
+
+// Clear stops the running mode and clears the display
+func (s *Server) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stop()
+	s.setState(State{Mode: "idle"})
+	return s.display.Clear()
+}
+
+// Stop stops the running mode and waits for it to finish
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stop()
+}
+
+// start stops the running mode and runs a new one in the background
+func (s *Server) start(state State, run func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stop()
+	s.setState(state)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	current := &serverRun{cancel: cancel, done: make(chan struct{})}
+	s.current = current
+
+	go func() {
+		err := run(ctx)
+		close(current.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.current != current {
+			// another mode has already been started
+			return
+		}
+		s.current = nil
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Errorf("Failed to run %s mode: %v", s.state.Mode, err)
+			s.state.Error = err.Error()
+			return
+		}
+		// static text stays on the display after it has been shown
+		if s.state.Mode != "clock" && !s.state.Static {
+			s.setState(State{Mode: "idle"})
+		}
+	}()
+}
+
+// stop cancels the running mode and waits for it to finish, s.mu must be held
+func (s *Server) stop() {
+	if s.current == nil {
+		return
+	}
+	s.current.cancel()
+	<-s.current.done
+	s.current = nil
+}
+
+// setState replaces the state keeping the display size, s.mu must be held
+func (s *Server) setState(state State) {
+	state.Width = s.display.Width()
+	state.Height = s.display.Height()
+	s.state = state
+}
+
+func (s *Server) handleText(w http.ResponseWriter, r *http.Request) {
+	req := TextRequest{Font: "large", ColumnsPerSecond: DefaultColumnsPerSecond, Align: "center", VAlign: "middle", Effect: "scroll-left"}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Text == "" {
+		http.Error(w, "text must not be empty", http.StatusBadRequest)
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Static {
+		err = s.StartStaticText(req.Text, opts)
+	} else {
+		err = s.StartTextEffect(req.Text, opts)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.writeState(w, http.StatusAccepted)
+}
+
+func (s *Server) handleFrame(w http.ResponseWriter, r *http.Request) {
+	var req FrameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	frame, err := s.parseFrame(req.Rows)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.ShowFrame(frame); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.writeState(w, http.StatusOK)
+}
+
+func (s *Server) handleClock(w http.ResponseWriter, r *http.Request) {
+	s.StartClock()
+	s.writeState(w, http.StatusAccepted)
+}
+
+func (s *Server) handleTestPattern(w http.ResponseWriter, r *http.Request) {
+	s.StartTestPattern()
+	s.writeState(w, http.StatusAccepted)
+}
+
+func (s *Server) handleClear(w http.ResponseWriter, r *http.Request) {
+	if err := s.Clear(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.writeState(w, http.StatusOK)
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	s.writeState(w, http.StatusOK)
+}
+
+func (s *Server) writeState(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(s.State()); err != nil {
+		log.Errorf("Failed to write state: %v", err)
+	}
+}
+
+// parseFrame creates a frame from rows of '#' (on) and '.' (off) characters, the size of the display
+func (s *Server) parseFrame(rows []string) (*Frame, error) {
+	if len(rows) != s.display.Height() {
+		return nil, fmt.Errorf("frame has %d rows, display is %d rows high", len(rows), s.display.Height())
+	}
+
+	frame := s.display.NewFrame()
+	for y, row := range rows {
+		if len(row) != s.display.Width() {
+			return nil, fmt.Errorf("row %d has %d columns, display is %d columns wide", y, len(row), s.display.Width())
+		}
+		for x, c := range row {
+			switch {
+			case strings.ContainsRune("#1Xx", c):
+				frame.SetPixel(x, y, true)
+			case strings.ContainsRune(". 0", c):
+			default:
+				return nil, fmt.Errorf("invalid character '%c' in row %d, use '#' for on and '.' for off", c, y)
+			}
+		}
+	}
+	return frame, nil
+}
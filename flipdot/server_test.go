@@ -0,0 +1,235 @@
+package flipdot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+)
+
+// waitForCalls waits until the mock has been shown at least n frames
+func waitForCalls(t *testing.T, mock *MockDisplayOutput, n int) []ShowCall {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if calls := mock.Calls(); len(calls) >= n {
+			return calls
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected at least %d Show calls, got %d", n, len(mock.Calls()))
+	return nil
+}
+
+func newTestServer(t *testing.T) (*Server, *MockDisplayOutput) {
+	mock := &MockDisplayOutput{}
+	server := NewServer(&Display{output: mock, layout: DefaultLayout()})
+	t.Cleanup(server.Stop)
+	return server, mock
+}
+
+func request(server *Server, method string, path string, body string) (*httptest.ResponseRecorder, State) {
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	var state State
+	json.Unmarshal(rec.Body.Bytes(), &state)
+	return rec, state
+}
+
+func TestServerText(t *testing.T) {
+	server, mock := newTestServer(t)
+
+	rec, state := request(server, "POST", "/text", `{"text": "Hi", "font": "small", "loop": true, "columns_per_second": 100}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body)
+	}
+	if state.Mode != "text" || state.Text != "Hi" || state.Font != "small" || !state.Loop {
+		t.Errorf("unexpected state %+v", state)
+	}
+	waitForCalls(t, mock, 2)
+
+	invalid := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{"text":`},
+		{name: "empty text", body: `{"text": ""}`},
+		{name: "invalid speed", body: `{"text": "Hi", "speed": 10}`},
+		{name: "invalid font", body: `{"text": "Hi", "font": "huge"}`},
+		{name: "unsupported character", body: `{"text": "🚀", "font": "small"}`},
+		{name: "invalid effect", body: `{"text": "Hi", "effect": "spin"}`},
+		{name: "invalid pause", body: `{"text": "Hi", "pause_start": "2"}`},
+		{name: "invalid columns per second", body: `{"text": "Hi", "columns_per_second": -1}`},
+		{name: "zero columns per second", body: `{"text": "Hi", "columns_per_second": 0}`},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, _ := request(server, "POST", "/text", tc.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+			}
+		})
+	}
+
+	// an invalid request does not stop the running text
+	_, state = request(server, "GET", "/state", "")
+	if state.Mode != "text" {
+		t.Errorf("expected text mode to still be running, got %+v", state)
+	}
+}
+
+func TestServerTextSubstitutions(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	server := NewServer(&Display{output: mock, layout: DefaultLayout(), textFallback: fonts.Fallback{StripAccents: true, Missing: fonts.MissingReplace}})
+	t.Cleanup(server.Stop)
+
+	rec, state := request(server, "POST", "/text", `{"text": "Über 🚀", "font": "large"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body)
+	}
+	expected := []string{"'Ü' as 'U' from large", "'🚀' as '?' from large"}
+	if !reflect.DeepEqual(state.Substitutions, expected) {
+		t.Errorf("expected substitutions %q, got %q", expected, state.Substitutions)
+	}
+}
+
+func TestServerStaticText(t *testing.T) {
+	server, mock := newTestServer(t)
+
+	rec, state := request(server, "POST", "/text", `{"text": "HI", "font": "small", "static": true, "align": "left"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body)
+	}
+	if state.Mode != "text" || !state.Static {
+		t.Errorf("unexpected state %+v", state)
+	}
+	calls := waitForCalls(t, mock, 1)
+	if calls[0].DisplayData[0] == 0 {
+		t.Errorf("expected the text at the left edge, got %b", calls[0].DisplayData)
+	}
+
+	// the text stays on the display
+	server.Stop()
+	if state := server.State(); state.Mode != "text" {
+		t.Errorf("expected text mode after the text was shown, got %+v", state)
+	}
+
+	rec, _ = request(server, "POST", "/text", `{"text": "HI", "static": true, "valign": "center"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an invalid alignment, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+// Test that the deprecated speed from 1 to 9 still sets the columns per second
+func TestTextRequestSpeed(t *testing.T) {
+	req := TextRequest{ColumnsPerSecond: DefaultColumnsPerSecond, Align: "center", VAlign: "middle", Effect: "scroll-left"}
+	opts, err := req.options()
+	if err != nil || opts.Speed != DefaultColumnsPerSecond {
+		t.Errorf("expected %d columns per second, got %g: %v", DefaultColumnsPerSecond, opts.Speed, err)
+	}
+	req.Speed = 9
+	opts, err = req.options()
+	if err != nil || opts.Speed != 100 {
+		t.Errorf("expected 100 columns per second for speed 9, got %g: %v", opts.Speed, err)
+	}
+	// only the speed that is set is checked
+	req.ColumnsPerSecond = 0
+	if _, err := req.options(); err != nil {
+		t.Errorf("unexpected error for speed 9 without columns per second: %v", err)
+	}
+}
+
+func TestServerTextFinishes(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	request(server, "POST", "/text", `{"text": "Hi", "font": "small", "columns_per_second": 100}`)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for server.State().Mode != "idle" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected idle mode after the text finished, got %+v", server.State())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerFrame(t *testing.T) {
+	server, mock := newTestServer(t)
+
+	rows := make([]string, 14)
+	for i := range rows {
+		rows[i] = strings.Repeat(".", 28)
+	}
+	rows[0] = "#" + strings.Repeat(".", 27)
+	rows[13] = strings.Repeat(".", 27) + "#"
+	body, _ := json.Marshal(FrameRequest{Rows: rows})
+
+	rec, state := request(server, "POST", "/frame", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if state.Mode != "frame" || state.Width != 28 || state.Height != 14 {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 Show call, got %d", len(calls))
+	}
+	if calls[0].DisplayData[0] != 0b1 || calls[0].DisplayData[27] != 1<<13 {
+		t.Errorf("unexpected display data %v", calls[0].DisplayData)
+	}
+
+	t.Run("wrong size", func(t *testing.T) {
+		rec, _ := request(server, "POST", "/frame", `{"rows": ["#."]}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("invalid character", func(t *testing.T) {
+		rows[3] = "?" + strings.Repeat(".", 27)
+		body, _ := json.Marshal(FrameRequest{Rows: rows})
+		rec, _ := request(server, "POST", "/frame", string(body))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestServerModes(t *testing.T) {
+	server, mock := newTestServer(t)
+
+	rec, state := request(server, "POST", "/mode/clock", "")
+	if rec.Code != http.StatusAccepted || state.Mode != "clock" {
+		t.Fatalf("unexpected response %d %+v", rec.Code, state)
+	}
+	waitForCalls(t, mock, 1)
+
+	// starting another mode stops the clock
+	rec, state = request(server, "POST", "/mode/test-pattern", "")
+	if rec.Code != http.StatusAccepted || state.Mode != "test-pattern" {
+		t.Fatalf("unexpected response %d %+v", rec.Code, state)
+	}
+
+	rec, state = request(server, "POST", "/clear", "")
+	if rec.Code != http.StatusOK || state.Mode != "idle" {
+		t.Fatalf("unexpected response %d %+v", rec.Code, state)
+	}
+	calls := mock.Calls()
+	for i, column := range calls[len(calls)-1].DisplayData {
+		if column != 0 {
+			t.Errorf("expected column %d to be blank after clear, got %b", i, column)
+		}
+	}
+
+	rec, _ = request(server, "GET", "/mode/clock", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -6,21 +6,25 @@ import (
 )
 
 // TerminalOutput implements DisplayOutput for terminal display
-type TerminalOutput struct{}
+type TerminalOutput struct {
+	height int
+}
 
 // Show method for TerminalOutput - matches DisplayOutput interface
-func (t *TerminalOutput) Show(displayData [28]uint16) error {
+func (t *TerminalOutput) Show(displayData []uint32) error {
+	width := len(displayData)
+
 	// Clear screen and move cursor to top
 	fmt.Print("\033[2J\033[H")
 
 	// Display the flipdot pattern as ASCII art
 	fmt.Println("Flipdot Display Output:")
-	fmt.Println("┌" + strings.Repeat("─", 28*3) + "┐")
+	fmt.Println("┌" + strings.Repeat("─", width*3) + "┐")
 
-	// Display all 14 rows (top 7 rows from lower bits, bottom 7 rows from upper bits)
-	for row := 0; row < 14; row++ {
+	// Display all rows, the top row is the lowest bit of each column
+	for row := 0; row < t.height; row++ {
 		fmt.Print("│")
-		for col := 0; col < 28; col++ {
+		for col := 0; col < width; col++ {
 			if displayData[col]&(1<<row) != 0 {
 				fmt.Print(" ● ")
 			} else {
@@ -30,7 +34,7 @@ func (t *TerminalOutput) Show(displayData [28]uint16) error {
 		fmt.Println("│")
 	}
 
-	fmt.Println("└" + strings.Repeat("─", 28*3) + "┘")
+	fmt.Println("└" + strings.Repeat("─", width*3) + "┘")
 	return nil
 }
 
@@ -0,0 +1,243 @@
+package flipdot
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"strings"
+	"time"
+
+	fonts "github.com/FutureSharks/flipdot-clock/flipdot/fonts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Align is the horizontal position of each line of static text
+type Align int
+
+const (
+	// AlignCenter centers the lines on the display
+	AlignCenter Align = iota
+	// AlignLeft starts the lines at the left edge of the display
+	AlignLeft
+	// AlignRight ends the lines at the right edge of the display
+	AlignRight
+)
+
+// VAlign is the vertical position of the lines of static text
+type VAlign int
+
+const (
+	// VAlignMiddle centers the lines vertically
+	VAlignMiddle VAlign = iota
+	// VAlignTop starts the lines at the top of the display
+	VAlignTop
+	// VAlignBottom ends the lines at the bottom of the display
+	VAlignBottom
+)
+
+var alignNames = map[string]Align{
+	"left":   AlignLeft,
+	"center": AlignCenter,
+	"right":  AlignRight,
+}
+
+var valignNames = map[string]VAlign{
+	"top":    VAlignTop,
+	"middle": VAlignMiddle,
+	"bottom": VAlignBottom,
+}
+
+// ParseAlign returns the alignment with a name of "left", "center" or "right"
+func ParseAlign(name string) (Align, error) {
+	align, ok := alignNames[name]
+	if !ok {
+		return 0, fmt.Errorf("alignment '%s' not supported, must be one of 'left', 'center' or 'right'", name)
+	}
+	return align, nil
+}
+
+// ParseVAlign returns the vertical alignment with a name of "top", "middle" or "bottom"
+func ParseVAlign(name string) (VAlign, error) {
+	valign, ok := valignNames[name]
+	if !ok {
+		return 0, fmt.Errorf("vertical alignment '%s' not supported, must be one of 'top', 'middle' or 'bottom'", name)
+	}
+	return valign, nil
+}
+
+// TextOptions are the settings for showing text with an effect or static text
+type TextOptions struct {
+	// Font is the name of a registered font
+	Font   string
+	Align  Align
+	VAlign VAlign
+	// Wrap breaks lines between words so they fit the width of the display.
+	// Lines are always broken at newlines.
+	Wrap bool
+	// Effect is how the text moves, static text that does not fit is shown with it instead
+	Effect TextEffect
+	// Speed is the number of columns the text moves per second, rows when scrolling up or down and
+	// characters for the typewriter. Zero moves it as fast as the output can show it.
+	Speed float64
+	Loop  bool
+	// PauseStart and PauseEnd hold the text when it is first and last in view, see ShowTextEffect
+	PauseStart time.Duration
+	PauseEnd   time.Duration
+}
+
+// textBlock is the rendered lines of static text
+type textBlock struct {
+	lines [][]uint16
+	// top is the highest row used by any of the lines and height the number of rows from it to the lowest
+	top    int
+	height int
+}
+
+// ShowStaticText shows text without scrolling, aligned and broken into lines as set in opts.
+// Text that does not fit on the display is shown with opts.Effect instead.
+func (d *Display) ShowStaticText(ctx context.Context, text string, opts TextOptions) error {
+	font, err := fonts.Lookup(opts.Font)
+	if err != nil {
+		return err
+	}
+
+	block, substitutions, err := d.layoutText(text, font, opts.Wrap)
+	if err != nil {
+		return err
+	}
+	spacing, fits := d.lineSpacing(block)
+	if !fits {
+		log.Debugf("Text does not fit on the display, showing it with an effect instead")
+		return d.ShowTextEffect(ctx, text, opts)
+	}
+	logSubstitutions(font, substitutions)
+
+	total := len(block.lines)*(block.height+spacing) - spacing
+	var y int
+	switch opts.VAlign {
+	case VAlignTop:
+		y = 0
+	case VAlignBottom:
+		y = d.Height() - total
+	default:
+		y = (d.Height() - total) / 2
+	}
+
+	displayData := make([]uint32, d.Width())
+	for _, line := range block.lines {
+		x := d.alignX(opts.Align, len(line))
+
+		// move the rows used by the text to the top of the line
+		columns := make([]uint16, len(line))
+		for i, column := range line {
+			columns[i] = column >> block.top
+		}
+		d.drawColumns(displayData, columns, x, y)
+		y += block.height + spacing
+	}
+
+	return d.Show(displayData)
+}
+
+// MeasureText returns the width in dots of the widest line of the text in the font registered as fontSize,
+// without the gap after the last character
+func (d *Display) MeasureText(text string, fontSize string) (int, error) {
+	font, err := fonts.Lookup(fontSize)
+	if err != nil {
+		return 0, err
+	}
+
+	width := 0
+	for _, line := range strings.Split(text, "\n") {
+		columns, _, err := d.renderText(line, font)
+		if err != nil {
+			return 0, err
+		}
+		width = max(width, len(columns))
+	}
+	return width, nil
+}
+
+// layoutText renders the lines of the text, broken at newlines and if wrap is set between words
+// so the lines fit the width of the display
+func (d *Display) layoutText(text string, font fonts.Font, wrap bool) (textBlock, []fonts.Substitution, error) {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if !wrap {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line == "" {
+				line = word
+				continue
+			}
+			columns, _, err := d.renderText(line+" "+word, font)
+			if err != nil {
+				return textBlock{}, nil, err
+			}
+			if len(columns) > d.Width() {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line += " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	block := textBlock{}
+	var substitutions []fonts.Substitution
+	var used uint16
+	for _, line := range lines {
+		columns, lineSubstitutions, err := d.renderText(line, font)
+		if err != nil {
+			return textBlock{}, nil, err
+		}
+		for _, column := range columns {
+			used |= column
+		}
+		block.lines = append(block.lines, columns)
+		substitutions = append(substitutions, lineSubstitutions...)
+	}
+
+	if used != 0 {
+		block.top = bits.TrailingZeros16(used)
+		block.height = bits.Len16(used) - block.top
+	}
+	return block, substitutions, nil
+}
+
+// lineSpacing returns the number of blank rows between the lines of the block, one if there is room,
+// and whether the block fits on the display
+func (d *Display) lineSpacing(block textBlock) (int, bool) {
+	for _, line := range block.lines {
+		if len(line) > d.Width() {
+			return 0, false
+		}
+	}
+
+	height := len(block.lines) * block.height
+	if height > d.Height() {
+		return 0, false
+	}
+	if height+len(block.lines)-1 <= d.Height() {
+		return 1, true
+	}
+	return 0, true
+}
+
+// alignX returns the first column of text that is width columns wide with the alignment
+func (d *Display) alignX(align Align, width int) int {
+	switch align {
+	case AlignLeft:
+		return 0
+	case AlignRight:
+		return d.Width() - width
+	default:
+		return (d.Width() - width) / 2
+	}
+}
@@ -0,0 +1,148 @@
+package flipdot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMeasureText(t *testing.T) {
+	display := &Display{output: &MockDisplayOutput{}, layout: DefaultLayout()}
+
+	// '1' is 6 columns and '2' is 8 columns wide in the large font, with a gap between them
+	width, err := display.MeasureText("12", "large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if width != 15 {
+		t.Errorf("expected a width of 15, got %d", width)
+	}
+
+	width, err = display.MeasureText("1\n12\n", "large")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if width != 15 {
+		t.Errorf("expected the width of the widest line, got %d", width)
+	}
+
+	if _, err := display.MeasureText("12", "huge"); err == nil {
+		t.Errorf("expected an error for an unknown font")
+	}
+}
+
+// rowsUsed returns the rows with a dot that is on in any of the columns
+func rowsUsed(columns []uint32) uint32 {
+	var used uint32
+	for _, column := range columns {
+		used |= column
+	}
+	return used
+}
+
+func TestShowStaticText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts TextOptions
+		// check is given the only frame shown
+		check func(t *testing.T, frame []uint32)
+	}{
+		{
+			name: "left aligned",
+			text: "H",
+			opts: TextOptions{Font: "small", Align: AlignLeft},
+			check: func(t *testing.T, frame []uint32) {
+				if frame[0] == 0 || frame[len(frame)-1] != 0 {
+					t.Errorf("expected the text at the left edge, got %b", frame)
+				}
+			},
+		},
+		{
+			name: "right aligned",
+			text: "H",
+			opts: TextOptions{Font: "small", Align: AlignRight},
+			check: func(t *testing.T, frame []uint32) {
+				if frame[0] != 0 || frame[len(frame)-1] == 0 {
+					t.Errorf("expected the text at the right edge, got %b", frame)
+				}
+			},
+		},
+		{
+			name: "centered",
+			text: "I",
+			opts: TextOptions{Font: "small"},
+			check: func(t *testing.T, frame []uint32) {
+				// 'I' is 5 columns wide in the small font with the bar in the middle, capitals are 7 rows high
+				if frame[13] != 0b1111111<<3 {
+					t.Errorf("expected the text in the center, got %b", frame)
+				}
+			},
+		},
+		{
+			name: "top",
+			text: "I",
+			opts: TextOptions{Font: "small", VAlign: VAlignTop},
+			check: func(t *testing.T, frame []uint32) {
+				if rowsUsed(frame) != 0b1111111 {
+					t.Errorf("expected the text at the top, got rows %b", rowsUsed(frame))
+				}
+			},
+		},
+		{
+			name: "bottom",
+			text: "I",
+			opts: TextOptions{Font: "small", VAlign: VAlignBottom},
+			check: func(t *testing.T, frame []uint32) {
+				if rowsUsed(frame) != 0b1111111<<7 {
+					t.Errorf("expected the text at the bottom, got rows %b", rowsUsed(frame))
+				}
+			},
+		},
+		{
+			name: "two lines",
+			text: "I\nI",
+			opts: TextOptions{Font: "small", Align: AlignLeft},
+			check: func(t *testing.T, frame []uint32) {
+				if frame[2] != 0b11111111111111 {
+					t.Errorf("expected two lines filling the 14 rows, got %b", frame[2])
+				}
+			},
+		},
+		{
+			name: "wrapped",
+			text: "ABC DEF",
+			opts: TextOptions{Font: "small", Wrap: true},
+			check: func(t *testing.T, frame []uint32) {
+				if rowsUsed(frame) != 0b11111111111111 {
+					t.Errorf("expected the words on two lines, got rows %b", rowsUsed(frame))
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &MockDisplayOutput{}
+			display := &Display{output: mock, layout: DefaultLayout()}
+			if err := display.ShowStaticText(context.Background(), tc.text, tc.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(mock.Calls()) != 1 {
+				t.Fatalf("expected 1 Show call, got %d", len(mock.Calls()))
+			}
+			tc.check(t, mock.Calls()[0].DisplayData)
+		})
+	}
+}
+
+func TestShowStaticTextScrolls(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout()}
+
+	// without wrapping the words are too wide for the display
+	if err := display.ShowStaticText(context.Background(), "ABC DEF", TextOptions{Font: "small"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.Calls()) < 28 {
+		t.Errorf("expected the text to scroll, got %d Show calls", len(mock.Calls()))
+	}
+}
@@ -0,0 +1,50 @@
+package flipdot
+
+import (
+	"context"
+	"time"
+)
+
+// frameTicker paces frames at a fixed rate. Frames are due at fixed times from when the ticker started,
+// so the time taken to show a frame does not slow the rate down.
+type frameTicker struct {
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+}
+
+// newFrameTicker creates a frameTicker for a rate in frames per second, a rate of 0 does not wait between frames
+func newFrameTicker(rate float64) *frameTicker {
+	t := &frameTicker{now: time.Now}
+	if rate > 0 {
+		t.interval = time.Duration(float64(time.Second) / rate)
+	}
+	t.reset()
+	return t
+}
+
+// reset makes the next frame due one interval from now, e.g. after a pause
+func (t *frameTicker) reset() {
+	t.next = t.now()
+}
+
+// wait waits until the next frame is due and returns how many frames to move on, more than one if frames
+// were due before the previous frame was shown and should be dropped to catch up with the rate
+func (t *frameTicker) wait(ctx context.Context) (int, error) {
+	t.next = t.next.Add(t.interval)
+	now := t.now()
+
+	steps := 1
+	if t.interval > 0 {
+		if late := now.Sub(t.next); late >= t.interval {
+			missed := int(late / t.interval)
+			t.next = t.next.Add(time.Duration(missed) * t.interval)
+			steps += missed
+		}
+	}
+
+	if err := sleep(ctx, t.next.Sub(now)); err != nil {
+		return 0, err
+	}
+	return steps, nil
+}
@@ -0,0 +1,88 @@
+package flipdot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeTicker returns a frameTicker with a clock that only moves when the returned function is called
+func fakeTicker(rate float64) (*frameTicker, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticker := newFrameTicker(rate)
+	ticker.now = func() time.Time { return now }
+	ticker.reset()
+	return ticker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestFrameTicker(t *testing.T) {
+	ctx := context.Background()
+	ticker, advance := fakeTicker(10)
+
+	// showing a frame takes less than the interval, the next frame is still due 100ms after the previous
+	advance(40 * time.Millisecond)
+	steps, err := ticker.wait(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps != 1 || !ticker.next.Equal(ticker.now().Add(60*time.Millisecond)) {
+		t.Errorf("expected 1 step due in 60ms, got %d due in %v", steps, ticker.next.Sub(ticker.now()))
+	}
+
+	// showing a frame takes 350ms, the frames due at 200ms and 300ms are dropped
+	// and the one due at 400ms, 50ms ago, is shown straight away
+	advance(60*time.Millisecond + 350*time.Millisecond)
+	steps, err = ticker.wait(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps != 3 {
+		t.Errorf("expected 3 steps, got %d", steps)
+	}
+	if !ticker.next.Equal(ticker.now().Add(-50 * time.Millisecond)) {
+		t.Errorf("expected the frame due 50ms ago, got %v", ticker.next.Sub(ticker.now()))
+	}
+
+	// after a pause frames are due from when it ended
+	advance(2 * time.Second)
+	ticker.reset()
+	advance(10 * time.Millisecond)
+	steps, err = ticker.wait(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps != 1 {
+		t.Errorf("expected 1 step after a reset, got %d", steps)
+	}
+}
+
+func TestFrameTickerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ticker := newFrameTicker(0.1)
+	if _, err := ticker.wait(ctx); err == nil {
+		t.Errorf("expected an error when cancelled")
+	}
+}
+
+func TestNextFrame(t *testing.T) {
+	frames := make([]effectFrame, 10)
+	frames[5].key = true
+
+	tests := []struct {
+		i, steps, expected int
+	}{
+		{i: 0, steps: 1, expected: 1},
+		{i: 0, steps: 3, expected: 3},
+		// key frames are not skipped
+		{i: 2, steps: 6, expected: 5},
+		{i: 5, steps: 2, expected: 7},
+		// nor is the last frame
+		{i: 7, steps: 5, expected: 9},
+	}
+	for _, tc := range tests {
+		if i := nextFrame(frames, tc.i, tc.steps); i != tc.expected {
+			t.Errorf("expected %d steps from frame %d to be frame %d, got %d", tc.steps, tc.i, tc.expected, i)
+		}
+	}
+}
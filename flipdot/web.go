@@ -0,0 +1,198 @@
+package flipdot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WebOutput implements DisplayOutput by serving a page that shows the display in a browser
+// The frames are streamed to the page with Server-Sent Events from /events
+type WebOutput struct {
+	width  int
+	height int
+	server *http.Server
+
+	mu      sync.Mutex
+	last    []uint32
+	clients map[chan []uint32]bool
+	closed  chan struct{}
+}
+
+// webFrame is the data sent to the page for every frame
+type webFrame struct {
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Columns []uint32 `json:"columns"`
+}
+
+// NewWebOutput creates a WebOutput for a display with the given layout
+// It is an http.Handler and does not listen on its own, see newWebOutput
+func NewWebOutput(layout Layout) *WebOutput {
+	return &WebOutput{
+		width:   layout.Width(),
+		height:  layout.Height(),
+		last:    make([]uint32, layout.Width()),
+		clients: map[chan []uint32]bool{},
+		closed:  make(chan struct{}),
+	}
+}
+
+// newWebOutput creates a WebOutput listening on address, e.g. ":8081"
+func newWebOutput(address string, layout Layout) (*WebOutput, error) {
+	w := NewWebOutput(layout)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %v", address, err)
+	}
+
+	w.server = &http.Server{Handler: w}
+	go func() {
+		err := w.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Web simulator stopped: %v", err)
+		}
+	}()
+	log.Infof("Web simulator listening on http://%s", listener.Addr())
+
+	return w, nil
+}
+
+func (w *WebOutput) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(rw, webPage)
+	case "/events":
+		w.serveEvents(rw, r)
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// Show method for WebOutput - sends the frame to every connected page
+func (w *WebOutput) Show(displayData []uint32) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.last = append([]uint32(nil), displayData...)
+	for client := range w.clients {
+		// only the newest frame matters, so replace a frame the page has not received yet
+		select {
+		case <-client:
+		default:
+		}
+		client <- w.last
+	}
+	return nil
+}
+
+// Close method for WebOutput
+func (w *WebOutput) Close() error {
+	w.mu.Lock()
+	select {
+	case <-w.closed:
+	default:
+		close(w.closed)
+	}
+	w.mu.Unlock()
+
+	if w.server != nil {
+		return w.server.Shutdown(context.Background())
+	}
+	return nil
+}
+
+func (w *WebOutput) serveEvents(rw http.ResponseWriter, r *http.Request) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan []uint32, 1)
+	w.mu.Lock()
+	client <- w.last
+	w.clients[client] = true
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.clients, client)
+		w.mu.Unlock()
+	}()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-w.closed:
+			return
+		case columns := <-client:
+			data, err := json.Marshal(webFrame{Width: w.width, Height: w.height, Columns: columns})
+			if err != nil {
+				log.Errorf("Failed to encode frame: %v", err)
+				return
+			}
+			_, err = fmt.Fprintf(rw, "data: %s\n\n", data)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// webPage draws the frames as round dots like the real XY5 panels
+const webPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Flipdot Display</title>
+<style>
+  body { background: #202020; color: #a0a0a0; font-family: sans-serif; display: flex; flex-direction: column; align-items: center; }
+  canvas { background: #111; border: 12px solid #000; border-radius: 4px; }
+</style>
+</head>
+<body>
+<h1>Flipdot Display</h1>
+<canvas id="display"></canvas>
+<p id="status">Connecting...</p>
+<script>
+  const dot = 20;
+  const canvas = document.getElementById("display");
+  const ctx = canvas.getContext("2d");
+  const status = document.getElementById("status");
+
+  function draw(frame) {
+    canvas.width = frame.width * dot;
+    canvas.height = frame.height * dot;
+    for (let x = 0; x < frame.width; x++) {
+      for (let y = 0; y < frame.height; y++) {
+        const on = (frame.columns[x] >>> y) & 1;
+        ctx.beginPath();
+        ctx.arc(x * dot + dot / 2, y * dot + dot / 2, dot * 0.42, 0, 2 * Math.PI);
+        ctx.fillStyle = on ? "#f4f0d0" : "#2a2a2a";
+        ctx.fill();
+      }
+    }
+  }
+
+  const events = new EventSource("events");
+  events.onopen = () => { status.textContent = "Connected"; };
+  events.onerror = () => { status.textContent = "Disconnected, retrying..."; };
+  events.onmessage = (e) => draw(JSON.parse(e.data));
+</script>
+</body>
+</html>
+`
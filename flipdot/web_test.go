@@ -0,0 +1,103 @@
+package flipdot
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readEvent reads the next Server-Sent Event and decodes its frame
+func readEvent(t *testing.T, reader *bufio.Reader) webFrame {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var frame webFrame
+		if err := json.Unmarshal([]byte(data), &frame); err != nil {
+			t.Fatalf("failed to decode event %q: %v", data, err)
+		}
+		return frame
+	}
+}
+
+func TestWebOutput(t *testing.T) {
+	web := NewWebOutput(DefaultLayout())
+	server := httptest.NewServer(web)
+	defer server.Close()
+	defer web.Close()
+
+	t.Run("page", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "EventSource") {
+			t.Errorf("unexpected page %d: %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/events")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+			t.Errorf("expected event stream, got %s", contentType)
+		}
+		reader := bufio.NewReader(resp.Body)
+
+		// a blank frame is sent as soon as the page connects
+		frame := readEvent(t, reader)
+		if frame.Width != 28 || frame.Height != 14 || !reflect.DeepEqual(frame.Columns, make([]uint32, 28)) {
+			t.Errorf("unexpected first frame %+v", frame)
+		}
+
+		displayData := make([]uint32, 28)
+		displayData[3] = 0b101
+		if err := web.Show(displayData); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		frame = readEvent(t, reader)
+		if !reflect.DeepEqual(frame.Columns, displayData) {
+			t.Errorf("expected %v, got %v", displayData, frame.Columns)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+}
+
+func TestNewDisplayWebOutput(t *testing.T) {
+	display, err := NewDisplay(Config{Outputs: []string{"web://127.0.0.1:0"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := display.output.(*WebOutput); !ok {
+		t.Errorf("expected WebOutput, got %T", display.output)
+	}
+	if err := display.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
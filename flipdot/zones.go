@@ -0,0 +1,76 @@
+package flipdot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultClockZoneDwell is how long the clock shows a time zone before the next unless ClockZone.Dwell is set
+const DefaultClockZoneDwell = 10 * time.Second
+
+// ClockZone is a time zone the clock shows the time of
+type ClockZone struct {
+	// Label is shown under the time in the mini font, e.g. "NYC". Empty shows no label.
+	Label    string
+	Location *time.Location
+	// Dwell is how long the zone is shown before the next, DefaultClockZoneDwell if zero
+	Dwell time.Duration
+}
+
+// LoadClockZone returns the clock zone of a time zone name from the IANA time zone database, e.g. "Europe/Berlin"
+func LoadClockZone(label string, name string) (ClockZone, error) {
+	if name == "" {
+		return ClockZone{}, fmt.Errorf("time zone missing")
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return ClockZone{}, fmt.Errorf("time zone '%s' not found, it must be a name like 'Europe/Berlin' from the time zone database, which must be installed, e.g. with the tzdata package: %v", name, err)
+	}
+	return ClockZone{Label: label, Location: location}, nil
+}
+
+// ParseClockZone returns the clock zone of "label=zone", e.g. "NYC=America/New_York", or of a zone without a label
+func ParseClockZone(spec string) (ClockZone, error) {
+	label, name, ok := strings.Cut(spec, "=")
+	if !ok {
+		label, name = "", spec
+	}
+	return LoadClockZone(label, name)
+}
+
+// dwell returns how long the zone is shown
+func (z ClockZone) dwell() time.Duration {
+	if z.Dwell <= 0 {
+		return DefaultClockZoneDwell
+	}
+	return z.Dwell
+}
+
+// clockZoneAt returns the zone the clock shows at t and when the next zone is shown, the zero time if there is
+// only one. The zones take turns from the Unix epoch, so the zone shown does not depend on when the clock started.
+// Without zones the time is shown in the location of t.
+func (d *Display) clockZoneAt(t time.Time) (ClockZone, time.Time) {
+	if len(d.clockZones) == 0 {
+		return ClockZone{Location: t.Location()}, time.Time{}
+	}
+	if len(d.clockZones) == 1 {
+		return d.clockZones[0], time.Time{}
+	}
+
+	var cycle time.Duration
+	for _, zone := range d.clockZones {
+		cycle += zone.dwell()
+	}
+	position := time.Duration(t.UnixNano()) % cycle
+	if position < 0 {
+		position += cycle
+	}
+	for _, zone := range d.clockZones {
+		if position < zone.dwell() {
+			return zone, t.Add(zone.dwell() - position)
+		}
+		position -= zone.dwell()
+	}
+	return d.clockZones[len(d.clockZones)-1], t.Add(cycle - position)
+}
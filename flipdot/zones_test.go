@@ -0,0 +1,107 @@
+package flipdot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseClockZone(t *testing.T) {
+	zone, err := ParseClockZone("TYO=Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone not available: %v", err)
+	}
+	if zone.Label != "TYO" || zone.Location.String() != "Asia/Tokyo" {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	zone, err = ParseClockZone("UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zone.Label != "" || zone.Location != time.UTC {
+		t.Errorf("unexpected zone %+v", zone)
+	}
+
+	for _, spec := range []string{"BER=Europe/Berln", "BER=", ""} {
+		if _, err := ParseClockZone(spec); err == nil {
+			t.Errorf("expected error for '%s'", spec)
+		}
+	}
+	// the error says what to install
+	if _, err := ParseClockZone("Europe/Berln"); err == nil || !strings.Contains(err.Error(), "tzdata") {
+		t.Errorf("expected the error to mention the time zone database, got %v", err)
+	}
+}
+
+func TestNewDisplayClockZoneWithoutLocation(t *testing.T) {
+	_, err := NewDisplay(Config{Outputs: []string{"terminal"}, ClockZones: []ClockZone{{Label: "NYC"}}})
+	if err == nil {
+		t.Error("expected error for a clock zone without a location")
+	}
+}
+
+func TestClockZoneAt(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	display := &Display{clockZones: []ClockZone{
+		{Label: "UTC", Location: time.UTC},
+		{Label: "TYO", Location: tokyo, Dwell: 20 * time.Second},
+	}}
+	start := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		t        time.Time
+		label    string
+		nextZone time.Time
+	}{
+		// the default dwell of 10 seconds is shown first, then 20 seconds of Tokyo
+		{t: start, label: "UTC", nextZone: start.Add(10 * time.Second)},
+		{t: start.Add(9 * time.Second), label: "UTC", nextZone: start.Add(10 * time.Second)},
+		{t: start.Add(10 * time.Second), label: "TYO", nextZone: start.Add(30 * time.Second)},
+		{t: start.Add(29 * time.Second), label: "TYO", nextZone: start.Add(30 * time.Second)},
+		{t: start.Add(30 * time.Second), label: "UTC", nextZone: start.Add(40 * time.Second)},
+	}
+	for _, tc := range testCases {
+		zone, nextZone := display.clockZoneAt(tc.t)
+		if zone.Label != tc.label || !nextZone.Equal(tc.nextZone) {
+			t.Errorf("expected %s until %v at %v, got %s until %v", tc.label, tc.nextZone, tc.t, zone.Label, nextZone)
+		}
+	}
+
+	// one zone is always shown
+	display.clockZones = display.clockZones[1:]
+	if zone, nextZone := display.clockZoneAt(start); zone.Label != "TYO" || !nextZone.IsZero() {
+		t.Errorf("expected only Tokyo, got %s until %v", zone.Label, nextZone)
+	}
+}
+
+// Test that the clock shows the time in each zone with its label and updates when the zone changes
+func TestRunClockZones(t *testing.T) {
+	mock := &MockDisplayOutput{}
+	display := &Display{output: mock, layout: DefaultLayout(), clockZones: []ClockZone{
+		{Label: "UTC", Location: time.UTC},
+		{Label: "TYO", Location: time.FixedZone("JST", 9*60*60)},
+	}}
+	clock := &fakeClock{now: time.Date(2024, time.March, 4, 10, 0, 55, 0, time.UTC), max: 4}
+	runFakeClock(t, display, clock)
+
+	// 10:00:55 is in the second half of the 20 second cycle, Tokyo until 10:01:00, UTC until 10:01:10
+	// and Tokyo again, the clock sleeps at most 5 seconds at a time
+	if calls := len(mock.Calls()); calls != 3 {
+		t.Fatalf("expected 3 Show calls, got %d", calls)
+	}
+
+	utc, err := display.clockFrame(time.Date(2024, time.March, 4, 10, 1, 0, 0, time.UTC), "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokyo, err := display.clockFrame(time.Date(2024, time.March, 4, 19, 0, 55, 0, time.UTC), "TYO")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := mock.Calls()
+	if !reflect.DeepEqual(calls[0].DisplayData, tokyo) || !reflect.DeepEqual(calls[1].DisplayData, utc) {
+		t.Errorf("expected Tokyo time and then UTC with their labels")
+	}
+}
@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/sirupsen/logrus v1.9.3
 	go.bug.st/serial v1.6.4
+	golang.org/x/text v0.21.0
 )
 
 require (
@@ -1,75 +1,319 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/FutureSharks/flipdot-clock/flipdot"
+	"github.com/FutureSharks/flipdot-clock/flipdot/fonts"
 
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
-	portName := flag.String("serial-port", "/dev/ttyS0", "The serial port connected to the displays")
-	baudRate := flag.Int("serial-baud", 57600, "The baud rate for the serial connection.")
-	terminalMode := flag.Bool("terminal", false, "Display output to terminal instead of serial port.")
-	testPattern := flag.Bool("test-pattern", false, "Display a test pattern and then exit")
-	clock := flag.Bool("clock", false, "Run the clock")
-	text := flag.String("text", "", "Display some text")
-	textLoop := flag.Bool("text-loop", false, "Loop text continuously")
-	textSize := flag.String("text-size", "large", "Size of each character. Value must be one of 'large' or 'small'")
-	scrollSpeed := flag.Int("text-scroll-speed", 5, "Text scroll speed. 1 is slow, 9 is fast")
-	debugLogging := flag.Bool("debug", false, "Enable debug logging")
+	o := &options{}
+	configPath := flag.String("config", "", "Path to a JSON config file, flags given on the command line override its settings")
+	flag.StringVar(&o.serialPort, "serial-port", "/dev/ttyS0", "The serial port connected to the displays")
+	flag.IntVar(&o.baudRate, "serial-baud", 57600, "The baud rate for the serial connection.")
+	flag.BoolVar(&o.serialBuffered, "serial-buffered", false, "Send all panels before showing them at once to avoid tearing between panels. Not supported by 7x7 modules.")
+	flag.DurationVar(&o.fullRefreshInterval, "full-refresh-interval", 0, "How often to send panels that have not changed, to correct stuck dots, e.g. '10m'. 0 means never.")
+	flag.BoolVar(&o.terminal, "terminal", false, "Also display output in the terminal, the same as '-output terminal'. Without -output only the terminal is used, not the serial port.")
+	flag.Var(&o.outputs, "output", "Where to send the display output, can be repeated to send to several outputs. Value must be one of 'serial' (the default), 'terminal', 'udp://host:port', 'tcp://host:port', 'web://[host]:port', 'gif://file.gif' or 'png://directory', optionally followed by '?on-error=fail', '?on-error=log' or '?on-error=disable'")
+	flag.StringVar(&o.layout, "layout", "1;2", "Addresses of the panels making up the display. Rows are separated by ';' and panels in a row by ','. An address can be followed by ':' and a module type, e.g. '1:7x14'")
+	flag.StringVar(&o.moduleType, "module-type", "7x28", "Module type of the panels. Value must be one of '7x7', '7x14' or '7x28'")
+	flag.IntVar(&o.panelRotation, "panel-rotation", 0, "Clockwise rotation of every panel in degrees. Value must be one of 0, 90, 180 or 270")
+	flag.BoolVar(&o.testPattern, "test-pattern", false, "Display a test pattern and then exit")
+	flag.BoolVar(&o.clock, "clock", false, "Run the clock")
+	flag.StringVar(&o.clockFace, "clock-face", flipdot.DefaultClockFace, fmt.Sprintf("How the clock shows the time. Value must be one of '%s'", strings.Join(flipdot.ClockFaceNames(), "', '")))
+	flag.StringVar(&o.clockFont, "clock-font", "", "Font of the digital clock face, e.g. 'tall' for digits the height of the display, the same as -clock-face tall. By default the small font, or the condensed font if the clock does not fit in it")
+	flag.StringVar(&o.clockFormat, "clock-format", flipdot.DefaultClockFormat, "Go time layout or strftime format of the clock, e.g. '3:04' or '%-I:%M' for a 12 hour clock")
+	flag.StringVar(&o.clockDate, "clock-date", "", "Go time layout or strftime format of a second row under the time in the mini font, e.g. 'Mon 2' or '%a %-d'")
+	flag.BoolVar(&o.clockPMDot, "clock-pm-dot", false, "Show a dot in the top left corner in the afternoon, for 12 hour clock formats")
+	flag.BoolVar(&o.clockSecondsBar, "clock-seconds-bar", false, "Show the seconds as a bar along the bottom row")
+	flag.Var(&o.clockZones, "clock-zone", "Time zone the clock shows with a label under the time, e.g. 'NYC=America/New_York', can be repeated to show the zones in turn")
+	flag.DurationVar(&o.clockZoneDwell, "clock-zone-dwell", flipdot.DefaultClockZoneDwell, "How long the clock shows each -clock-zone")
+	flag.StringVar(&o.text, "text", "", "Display some text")
+	flag.BoolVar(&o.textLoop, "text-loop", false, "Loop text continuously")
+	flag.BoolVar(&o.textStatic, "text-static", false, "Show the text without scrolling if it fits on the display, lines are broken at newlines")
+	flag.StringVar(&o.textAlign, "text-align", "center", "Horizontal alignment of static text. Value must be one of 'left', 'center' or 'right'")
+	flag.StringVar(&o.textVAlign, "text-valign", "middle", "Vertical alignment of static text. Value must be one of 'top', 'middle' or 'bottom'")
+	flag.StringVar(&o.textEffect, "text-effect", "scroll-left", "How the text moves. Value must be one of 'scroll-left', 'scroll-right', 'scroll-up', 'scroll-down', 'bounce' or 'typewriter'")
+	flag.DurationVar(&o.pauseStart, "text-pause-start", 0, "How long to hold the text when it first fills the display, e.g. '2s'")
+	flag.DurationVar(&o.pauseEnd, "text-pause-end", 0, "How long to hold the text when it last fills the display, e.g. '2s'")
+	flag.BoolVar(&o.textWrap, "text-wrap", false, "Break static text between words so the lines fit the width of the display")
+	flag.BoolVar(&o.stripAccents, "text-strip-accents", true, "Show letters with accents that are not in the font without them, e.g. 'é' as 'e'")
+	flag.Var(&o.fallbackFonts, "text-fallback-font", "Font to take characters from when they are not in the font of the text, can be repeated")
+	flag.StringVar(&o.missingChars, "text-missing", "replace", "What to do with characters that are not in any font. Value must be one of 'error', 'replace' (with '?') or 'skip'")
+	flag.StringVar(&o.textSize, "text-size", "large", fmt.Sprintf("Font of the text. Value must be one of '%s' or the name of a font loaded with -font-file", strings.Join(fonts.Names(), "', '")))
+	flag.Var(&o.fontFiles, "font-file", "Load a BDF or PSF font, can be repeated. The font is named after the file, e.g. '-font-file 6x13.bdf -text-size 6x13'")
+	flag.Float64Var(&o.columnsPerSecond, "text-columns-per-second", flipdot.DefaultColumnsPerSecond, "How fast the text moves in columns per second, rows per second when scrolling up or down and characters per second for typewriter")
+	flag.Var(scrollSpeedFlag{&o.columnsPerSecond}, "text-scroll-speed", "Deprecated, use -text-columns-per-second. Sets -text-columns-per-second from a speed between 1 (slow) and 9 (fast), 5 is 11 columns per second")
+	flag.StringVar(&o.httpListen, "http-listen", "", "Run as a daemon with an HTTP API listening on this address, e.g. ':8080'. -clock or -text set what is shown at startup")
+	flag.BoolVar(&o.debug, "debug", false, "Enable debug logging")
 
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "flipdot-clock: a small tool for displaying text or the time on a Alfa-Zeta XY5 14*28 flipdot display\n\n")
+		fmt.Fprintf(os.Stderr, "flipdot-clock: a small tool for displaying text or the time on an Alfa-Zeta XY5 flipdot display\n\n")
 		flag.PrintDefaults()
 	}
 
 	flag.Parse()
 
-	if *textSize != "large" && *textSize != "small" {
-		log.Fatalf("Invalid text-size value %s. Must be 'large' or 'small'", *textSize)
+	for _, path := range o.fontFiles {
+		font, err := fonts.LoadFont(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Debugf("Loaded font %s", font.Name())
+	}
+
+	if *configPath != "" {
+		config, err := loadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		setFlags := map[string]bool{}
+		flag.Visit(func(f *flag.Flag) {
+			setFlags[f.Name] = true
+		})
+		err = config.apply(o, setFlags)
+		if err != nil {
+			log.Fatalf("Invalid config file %s: %v", *configPath, err)
+		}
+	}
+
+	if _, err := fonts.Lookup(o.textSize); err != nil {
+		log.Fatalf("Invalid text-size value %s: %v", o.textSize, err)
 	}
 
-	if *scrollSpeed < 1 || *scrollSpeed > 9 {
-		log.Fatalf("Invalid scroll-speed value %d. Must be between 1 and 9.", *scrollSpeed)
+	if o.clockFont != "" {
+		if _, err := fonts.Lookup(o.clockFont); err != nil {
+			log.Fatalf("Invalid clock-font value %s: %v", o.clockFont, err)
+		}
 	}
 
-	if *debugLogging {
+	align, err := flipdot.ParseAlign(o.textAlign)
+	if err != nil {
+		log.Fatalf("Invalid text-align value %s: %v", o.textAlign, err)
+	}
+	valign, err := flipdot.ParseVAlign(o.textVAlign)
+	if err != nil {
+		log.Fatalf("Invalid text-valign value %s: %v", o.textVAlign, err)
+	}
+	effect, err := flipdot.ParseTextEffect(o.textEffect)
+	if err != nil {
+		log.Fatalf("Invalid text-effect value %s: %v", o.textEffect, err)
+	}
+	if o.clockZoneDwell <= 0 {
+		log.Fatalf("Invalid clock-zone-dwell value %v. Must be positive.", o.clockZoneDwell)
+	}
+	for i := range o.clockZones {
+		if o.clockZones[i].Dwell == 0 {
+			o.clockZones[i].Dwell = o.clockZoneDwell
+		}
+	}
+	if o.pauseStart < 0 || o.pauseEnd < 0 {
+		log.Fatalf("Invalid text pause, must not be negative")
+	}
+	missing, err := fonts.ParseMissingStrategy(o.missingChars)
+	if err != nil {
+		log.Fatalf("Invalid text-missing value %s: %v", o.missingChars, err)
+	}
+	for _, name := range o.fallbackFonts {
+		if _, err := fonts.Lookup(name); err != nil {
+			log.Fatalf("Invalid text-fallback-font value %s: %v", name, err)
+		}
+	}
+
+	if o.columnsPerSecond <= 0 {
+		log.Fatalf("Invalid text-columns-per-second value %g. Must be positive.", o.columnsPerSecond)
+	}
+
+	if o.debug {
 		log.SetLevel(log.DebugLevel)
 	}
 
+	layout := flipdot.Layout{Panels: o.panels}
+	if len(layout.Panels) == 0 {
+		module, err := flipdot.ModuleTypeByName(o.moduleType)
+		if err != nil {
+			log.Fatalf("Invalid module-type value %s: %v", o.moduleType, err)
+		}
+
+		layout, err = flipdot.ParseLayout(o.layout, module, o.panelRotation)
+		if err != nil {
+			log.Fatalf("Invalid layout value %s: %v", o.layout, err)
+		}
+	}
+
 	// Create a new display instance
-	display, err := flipdot.NewDisplay(*terminalMode, *portName, *baudRate)
+	display, err := flipdot.NewDisplay(flipdot.Config{
+		Terminal:            o.terminal,
+		Outputs:             o.outputs,
+		SerialPort:          o.serialPort,
+		BaudRate:            o.baudRate,
+		Buffered:            o.serialBuffered,
+		Layout:              layout,
+		FullRefreshInterval: o.fullRefreshInterval,
+		ClockFace:           o.clockFace,
+		ClockFormat:         o.clockFormat,
+		ClockFont:           o.clockFont,
+		ClockDate:           o.clockDate,
+		ClockPMDot:          o.clockPMDot,
+		ClockSecondsBar:     o.clockSecondsBar,
+		ClockZones:          o.clockZones,
+		ClockSchedule:       o.clockSchedule,
+		TextFallback: fonts.Fallback{
+			StripAccents: o.stripAccents,
+			Fonts:        o.fallbackFonts,
+			Missing:      missing,
+		},
+	})
 
 	if err != nil {
 		log.Fatalf("Failed to create display: %v", err)
 	}
 	defer display.Close()
 
-	if *testPattern {
-		err = display.RunTestPattern()
-		if err != nil {
+	// Stop the running mode on Ctrl+C or when the service is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	textOptions := flipdot.TextOptions{
+		Font:       o.textSize,
+		Align:      align,
+		VAlign:     valign,
+		Wrap:       o.textWrap,
+		Effect:     effect,
+		Speed:      o.columnsPerSecond,
+		Loop:       o.textLoop,
+		PauseStart: o.pauseStart,
+		PauseEnd:   o.pauseEnd,
+	}
+
+	if o.httpListen != "" {
+		runServer(ctx, display, o.httpListen, func(server *flipdot.Server) error {
+			if o.text != "" && o.textStatic {
+				return server.StartStaticText(o.text, textOptions)
+			}
+			if o.text != "" {
+				return server.StartTextEffect(o.text, textOptions)
+			}
+			if o.clock {
+				server.StartClock()
+			}
+			return nil
+		})
+	} else if o.testPattern {
+		err = display.RunTestPatternContext(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
 			log.Fatalf("Failed to run test pattern: %v", err)
 		}
-	} else if *text != "" {
-		sleepDuration := time.Duration(190-(*scrollSpeed*20)) * time.Millisecond
-		err = display.ShowText(*text, sleepDuration, *textLoop, *textSize)
-		if err != nil {
+	} else if o.text != "" && o.textStatic {
+		err = display.ShowStaticText(ctx, o.text, textOptions)
+		if err != nil && !errors.Is(err, context.Canceled) {
 			log.Fatalf("Failed to show text: %v", err)
 		}
-	} else if *clock {
-		for {
-			err = display.ShowTime()
-			if err != nil {
-				log.Fatalf("Failed to show time: %v", err)
-			}
-			time.Sleep(1 * time.Minute)
+	} else if o.text != "" {
+		err = display.ShowTextEffect(ctx, o.text, textOptions)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Fatalf("Failed to show text: %v", err)
+		}
+	} else if o.clock {
+		err = display.RunClock(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Fatalf("Failed to show time: %v", err)
 		}
 	} else {
-		log.Infoln("No mode selected. Use '-clock' or '-text' arguments. Exiting.")
+		log.Infoln("No mode selected. Use '-clock', '-text' or '-http-listen' arguments. Exiting.")
+	}
+
+	if ctx.Err() != nil {
+		log.Infoln("Stopping, clearing the display")
+		err = display.Clear()
+		if err != nil {
+			log.Errorf("Failed to clear display: %v", err)
+		}
+	}
+}
+
+// runServer serves the HTTP API until ctx is cancelled
+func runServer(ctx context.Context, display *flipdot.Display, address string, startup func(server *flipdot.Server) error) {
+	server := flipdot.NewServer(display)
+	defer server.Stop()
+
+	err := startup(server)
+	if err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+
+	httpServer := &http.Server{Addr: address, Handler: server}
+	go func() {
+		<-ctx.Done()
+		httpServer.Shutdown(context.Background())
+	}()
+
+	log.Infof("HTTP API listening on %s", address)
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Failed to run HTTP API: %v", err)
+	}
+}
+
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// scrollSpeedFlag is the deprecated -text-scroll-speed flag, it sets the columns per second of a speed from 1 to 9
+type scrollSpeedFlag struct {
+	columnsPerSecond *float64
+}
+
+func (s scrollSpeedFlag) String() string {
+	return ""
+}
+
+func (s scrollSpeedFlag) Set(value string) error {
+	speed, err := strconv.Atoi(value)
+	if err != nil || speed < 1 || speed > 9 {
+		return fmt.Errorf("must be between 1 and 9")
+	}
+	*s.columnsPerSecond = flipdot.ScrollSpeed(speed)
+	log.Warnf("-text-scroll-speed is deprecated, use -text-columns-per-second %g", *s.columnsPerSecond)
+	return nil
+}
+
+// clockZoneList is a -clock-zone flag that can be repeated
+type clockZoneList []flipdot.ClockZone
+
+func (z *clockZoneList) String() string {
+	var specs []string
+	for _, zone := range *z {
+		specs = append(specs, zone.Label+"="+zone.Location.String())
+	}
+	return strings.Join(specs, ", ")
+}
+
+func (z *clockZoneList) Set(value string) error {
+	zone, err := flipdot.ParseClockZone(value)
+	if err != nil {
+		return err
 	}
+	*z = append(*z, zone)
+	return nil
 }